@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/protocol"
+	"github.com/aitoroses/battlestation-codetest/internal/platform/config"
+	httpPlatform "github.com/aitoroses/battlestation-codetest/internal/platform/http"
+	"github.com/aitoroses/battlestation-codetest/internal/platform/metrics"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to JSON config file (overrides "+config.EnvConfigPath+")")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		logger.Error("Failed to load config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	if err := run(cfg, logger); err != nil {
+		logger.Error("Server stopped with error", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
+// run wires the battle station components and serves until a shutdown signal arrives
+func run(cfg *config.Config, logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Build ion cannons
+	client := httpPlatform.NewCannonClient(cfg.CannonTimeout.Duration)
+	breaker := cannon.BreakerConfig{
+		FailureThreshold: cfg.Breaker.FailureThreshold,
+		OpenTimeout:      cfg.Breaker.OpenTimeout.Duration,
+		SuccessThreshold: cfg.Breaker.SuccessThreshold,
+		OnStateChange: func(generation cannon.Generation, state cannon.BreakerState) {
+			logger.Warn("Cannon circuit breaker changed state",
+				slog.Int("generation", int(generation)),
+				slog.String("state", state.String()),
+			)
+			metrics.UpdateCannonCircuitState(strconv.Itoa(int(generation)), float64(state))
+		},
+	}
+
+	cannons := make([]*cannon.IonCannon, 0, len(cfg.Cannons))
+	for _, c := range cfg.Cannons {
+		cannons = append(cannons, cannon.NewIonCannon(
+			cannon.Generation(c.Generation), c.URL, client,
+			cannon.WithCircuitBreaker(breaker),
+			cannon.WithStaleGrace(cfg.StaleGrace.Duration),
+			cannon.WithStatusTimeout(cfg.StatusTimeout.Duration),
+			cannon.WithFireTimeout(cfg.FireTimeout.Duration),
+			cannon.WithObserver(metrics.CannonObserver{}),
+			cannon.WithBlastRadius(c.BlastRadius),
+		))
+		metrics.UpdateCannonCircuitState(strconv.Itoa(c.Generation), float64(cannon.BreakerClosed))
+	}
+
+	// Build domain services
+	for _, p := range cfg.Protocols {
+		def, err := p.Definition()
+		if err != nil {
+			return err
+		}
+		if err := protocol.Register(def); err != nil {
+			return fmt.Errorf("failed to register protocol: %w", err)
+		}
+	}
+	manager := cannon.NewManager(cannons, cannon.WithLeaseTimeout(cfg.LeaseTimeout.Duration))
+	coordinator := attack.NewCoordinator(manager,
+		attack.WithMaxAttempts(cfg.MaxAttempts),
+		attack.WithRequestTimeout(cfg.RequestTimeout.Duration),
+		attack.WithTieBreaker(protocol.TieBreaker(cfg.TieBreaker)),
+		attack.WithNoFireZones(cfg.NoFireZones...),
+		attack.WithProtocolObserver(metrics.ProtocolObserver{}),
+	)
+
+	// Keep cannon status warm in the background until shutdown
+	pollCtx, stopPolling := context.WithCancel(ctx)
+	var pollers sync.WaitGroup
+	pollers.Add(1)
+	go func() {
+		defer pollers.Done()
+		manager.RunPoller(pollCtx, cfg.PollInterval.Duration)
+	}()
+	defer pollers.Wait()
+	defer stopPolling()
+
+	// Run asynchronous attack jobs. Their context outlives the shutdown
+	// signal so running jobs can drain before it is cancelled.
+	jobCtx, stopJobs := context.WithCancel(context.WithoutCancel(ctx))
+	jobs := attack.NewJobRunner(jobCtx, coordinator,
+		attack.NewJobStore(cfg.MaxJobs, cfg.JobTTL.Duration), cfg.JobWorkers,
+		attack.WithJobObserver(httpPlatform.RequestMetrics{}))
+	defer jobs.Wait()
+	defer stopJobs()
+
+	// Register routes
+	mux := http.NewServeMux()
+	httpPlatform.NewHandler(coordinator, logger,
+		httpPlatform.WithFleetStatus(manager),
+		httpPlatform.WithAttackJobs(jobs),
+		httpPlatform.WithIdempotencyWindow(cfg.IdempotencyWindow.Duration),
+		httpPlatform.WithIdempotencyKeys(cfg.IdempotencyKeys),
+	).RegisterRoutes(mux)
+	mux.Handle("GET /metrics", promhttp.Handler())
+
+	server := &http.Server{
+		Addr:    cfg.ListenAddr,
+		Handler: mux,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("Battle station listening",
+			slog.String("addr", cfg.ListenAddr),
+			slog.Int("cannons", len(cannons)),
+		)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down battle station")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout.Duration)
+	defer cancel()
+
+	err := server.Shutdown(shutdownCtx)
+
+	// Let submitted jobs finish within the same grace period; the deferred
+	// stopJobs cancels whatever is still running after it
+	if jobErr := jobs.Shutdown(shutdownCtx); jobErr != nil {
+		logger.Warn("Attack jobs still running at shutdown timeout", slog.String("error", jobErr.Error()))
+	}
+	return err
+}
@@ -40,6 +40,11 @@ func (c *IonCannon) isAvailable() bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	return c.availableLocked()
+}
+
+// availableLocked reports availability; callers must hold c.mu
+func (c *IonCannon) availableLocked() bool {
 	if c.lastFired.IsZero() {
 		return true
 	}
@@ -71,7 +76,7 @@ func (c *IonCannon) handleFire(w http.ResponseWriter, r *http.Request) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if !c.isAvailable() {
+	if !c.availableLocked() {
 		http.Error(w, "Cannon not available", http.StatusServiceUnavailable)
 		return
 	}
@@ -2,7 +2,12 @@ package attack
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
 	"github.com/aitoroses/battlestation-codetest/internal/domain/protocol"
@@ -11,15 +16,58 @@ import (
 
 // Request represents an attack request
 type Request struct {
-	Protocols []string    `json:"protocols"`
-	Scan      []ScanPoint `json:"scan"`
+	Protocols []string        `json:"protocols"`
+	Scan      []ScanPoint     `json:"scan"`
+	Explain   bool            `json:"explain,omitempty"`
+	Station   *target.Station `json:"station,omitempty"`
+	// Mode selects chain (default) or score target selection
+	Mode Mode `json:"mode,omitempty"`
+	// Weights scales each protocol's score in score mode; unlisted protocols weigh 1
+	Weights map[string]float64 `json:"weights,omitempty"`
+	// TieBreaker orders targets the protocols leave tied; empty uses the coordinator's policy
+	TieBreaker protocol.TieBreaker `json:"tie_breaker,omitempty"`
+	// NoFireZones are never attacked, in addition to the configured zones
+	NoFireZones []target.Zone `json:"no_fire_zones,omitempty"`
+}
+
+// station returns the station the request is evaluated from
+func (r *Request) station() target.Station {
+	if r.Station == nil {
+		return target.DefaultStation()
+	}
+	return *r.Station
 }
 
 // ScanPoint represents a single point in the scan data
 type ScanPoint struct {
-	Coordinates target.Position   `json:"coordinates"`
-	Enemies     target.EnemyGroup `json:"enemies"`
-	Allies      *int              `json:"allies,omitempty"`
+	Coordinates target.Position    `json:"coordinates"`
+	Enemies     target.EnemyGroups `json:"enemies"`
+	Allies      *int               `json:"allies,omitempty"`
+
+	// singleGroup records that enemies was sent as a single group object,
+	// so validation errors name the fields the client sent
+	singleGroup bool
+}
+
+// UnmarshalJSON decodes a scan point, remembering the form enemies were sent in
+func (p *ScanPoint) UnmarshalJSON(data []byte) error {
+	type scanPoint ScanPoint
+	var raw struct {
+		scanPoint
+		Enemies json.RawMessage `json:"enemies"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*p = ScanPoint(raw.scanPoint)
+	if raw.Enemies != nil {
+		if err := json.Unmarshal(raw.Enemies, &p.Enemies); err != nil {
+			return err
+		}
+		p.singleGroup = target.IsSingleGroup(raw.Enemies)
+	}
+	return nil
 }
 
 // Response represents the attack response
@@ -27,120 +75,613 @@ type Response struct {
 	Target     target.Position `json:"target"`
 	Casualties int             `json:"casualties"`
 	Generation int             `json:"generation"`
+	Score      *float64        `json:"score,omitempty"`
+	Excluded   []Exclusion     `json:"excluded,omitempty"`
+	Trace      []TraceStep     `json:"trace,omitempty"`
+	Scores     []ScoredTarget  `json:"scores,omitempty"`
+	Attempts   []Attempt       `json:"attempts,omitempty"`
+}
+
+// TraceStep records the targets a protocol received and kept, when explain is requested
+type TraceStep struct {
+	Protocol  string         `json:"protocol"`
+	Input     []TracedTarget `json:"input"`
+	Surviving []TracedTarget `json:"surviving"`
+}
+
+// TracedTarget identifies a target by its index in the original scan
+type TracedTarget struct {
+	Index       int             `json:"index"`
+	Coordinates target.Position `json:"coordinates"`
+}
+
+// SalvoResponse represents the outcome of firing every available cannon
+type SalvoResponse struct {
+	Shots    []Shot      `json:"shots"`
+	Excluded []Exclusion `json:"excluded,omitempty"`
+}
+
+// Shot represents the result of a single cannon in a salvo
+type Shot struct {
+	Target     target.Position `json:"target"`
+	Casualties int             `json:"casualties"`
+	Generation int             `json:"generation"`
+	Error      string          `json:"error,omitempty"`
 }
 
 // CannonManager defines the interface for managing ion cannons
 type CannonManager interface {
 	GetBestAvailable(ctx context.Context) (*cannon.IonCannon, error)
-	Fire(ctx context.Context, c *cannon.IonCannon, req *cannon.FireRequest) (*cannon.FireResponse, error)
+	Reserve(ctx context.Context, exclude ...*cannon.IonCannon) (*cannon.Reservation, error)
+	ReserveN(ctx context.Context, n int) ([]*cannon.Reservation, error)
+	Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error)
+	Release(r *cannon.Reservation)
 }
 
+// DefaultRequestTimeout is the total time budget of a request (OBR-003 BR-3)
+const DefaultRequestTimeout = time.Second
+
 // Coordinator orchestrates the attack process
 type Coordinator struct {
-	cannonManager CannonManager
+	cannonManager  CannonManager
+	maxAttempts    int
+	requestTimeout time.Duration
+	tieBreaker     protocol.TieBreaker
+	noFireZones    []target.Zone
+	observer       protocol.Observer
+}
+
+// Option configures optional Coordinator behaviour
+type Option func(*Coordinator)
+
+// WithMaxAttempts sets how many cannons an attack tries when firing fails.
+// A value of 1 disables failover.
+func WithMaxAttempts(n int) Option {
+	return func(c *Coordinator) {
+		c.maxAttempts = max(n, 1)
+	}
+}
+
+// WithRequestTimeout sets the total time budget of a request, covering
+// cannon selection, firing and failover. Zero disables it.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Coordinator) {
+		c.requestTimeout = d
+	}
+}
+
+// WithTieBreaker sets the policy that orders targets the protocols leave
+// tied, for requests that do not choose one
+func WithTieBreaker(tb protocol.TieBreaker) Option {
+	return func(c *Coordinator) {
+		c.tieBreaker = tb
+	}
+}
+
+// WithProtocolObserver reports the protocols applied to select each target to o
+func WithProtocolObserver(o protocol.Observer) Option {
+	return func(c *Coordinator) {
+		c.observer = o
+	}
 }
 
 // NewCoordinator creates a new attack coordinator
-func NewCoordinator(cannonManager CannonManager) *Coordinator {
-	return &Coordinator{
-		cannonManager: cannonManager,
+func NewCoordinator(cannonManager CannonManager, opts ...Option) *Coordinator {
+	c := &Coordinator{
+		cannonManager:  cannonManager,
+		maxAttempts:    DefaultMaxAttempts,
+		requestTimeout: DefaultRequestTimeout,
+		tieBreaker:     protocol.DefaultTieBreaker,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
+
+// Plan describes the attack that would be carried out for a request
+type Plan struct {
+	Target     target.Position `json:"target"`
+	Generation int             `json:"generation"`
+	Reason     string          `json:"reason"`
+	Score      *float64        `json:"score,omitempty"`
+	Excluded   []Exclusion     `json:"excluded,omitempty"`
+	Trace      []TraceStep     `json:"trace,omitempty"`
+	Scores     []ScoredTarget  `json:"scores,omitempty"`
+}
+
+// selection holds the outcome of running the protocols over a scan
+type selection struct {
+	target     *target.Target
+	chain      []protocol.Protocol
+	mode       Mode
+	weights    map[string]float64
+	tieBreaker protocol.TieBreaker
+	observer   protocol.Observer
+	targets    []*target.Target
+	indices    map[*target.Target]int
+	inRange    int
+	excluded   []Exclusion
+	candidates []*target.Target
+
+	blastRadius float64
+	allied      []target.Position
+	trace       []TraceStep
+	score       *float64
+	scores      []ScoredTarget
 }
 
 // ProcessAttack handles the complete attack sequence
 func (c *Coordinator) ProcessAttack(ctx context.Context, req *Request) (*Response, error) {
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	resp, err := c.processAttack(ctx, req, func(JobStatus) {})
+	if err != nil {
+		return nil, deadlineError(ctx, err)
+	}
+	return resp, nil
+}
+
+// processAttack runs an attack, calling progress as it enters the selecting
+// and firing phases
+func (c *Coordinator) processAttack(ctx context.Context, req *Request, progress func(JobStatus)) (*Response, error) {
+	progress(JobSelecting)
+
+	// 1-2. Build the protocol chain and the targets in range
+	sel, err := c.scanTargets(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Reserve best available cannon so concurrent requests get a different one
+	reservation, err := c.cannonManager.Reserve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannon selection failed: %w", err)
+	}
+
+	// 4. Select target, judging collateral damage by the cannon's blast radius
+	if err := sel.choose(req, reservation.Cannon.BlastRadius()); err != nil {
+		c.cannonManager.Release(reservation)
+		return nil, err
+	}
+	selectedTarget := sel.target
+
+	// 5. Fire cannon at target, failing over to the next cannon on errors
+	fireReq := &cannon.FireRequest{
+		Target:  selectedTarget.Coordinates,
+		Enemies: selectedTarget.EnemyCount(),
+	}
+
+	progress(JobFiring)
+	fireResp, attempts, err := c.fire(ctx, reservation, fireReq, sel.safeFor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 6. Prepare response, listing attempts only when failover kicked in
+	resp := &Response{
+		Target:     selectedTarget.Coordinates,
+		Casualties: fireResp.Casualties,
+		Generation: fireResp.Generation,
+		Score:      sel.score,
+		Excluded:   sel.excluded,
+		Trace:      sel.trace,
+		Scores:     sel.scores,
+	}
+	if len(attempts) > 1 {
+		resp.Attempts = attempts
+	}
+	return resp, nil
+}
+
+// ProcessSalvo fires every available cannon at a distinct target.
+// Targets are ranked by the protocols and assigned to cannons by generation
+// priority, so the best target gets the best cannon.
+// Failed shots are reported per cannon; an error is returned only when no
+// shot succeeds.
+func (c *Coordinator) ProcessSalvo(ctx context.Context, req *Request) (*SalvoResponse, error) {
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	resp, err := c.processSalvo(ctx, req)
+	if err != nil {
+		return nil, deadlineError(ctx, err)
+	}
+	return resp, nil
+}
+
+func (c *Coordinator) processSalvo(ctx context.Context, req *Request) (*SalvoResponse, error) {
+	sel, err := c.scanTargets(req)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := c.cannonManager.ReserveN(ctx, len(sel.targets))
+	if err != nil {
+		return nil, fmt.Errorf("cannon selection failed: %w", err)
+	}
+
+	// Select targets safe for the widest blast radius in the salvo
+	blastRadius := 0.0
+	for _, r := range reservations {
+		blastRadius = max(blastRadius, r.Cannon.BlastRadius())
+	}
+
+	err = sel.choose(req, blastRadius)
+	if err == nil {
+		err = sel.rank()
+	}
+	if err != nil {
+		for _, r := range reservations {
+			c.cannonManager.Release(r)
+		}
+		return nil, err
+	}
+
+	n := min(len(sel.candidates), len(reservations))
+	for _, r := range reservations[n:] {
+		c.cannonManager.Release(r)
+	}
+	shots := make([]Shot, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int, reservation *cannon.Reservation, t *target.Target) {
+			defer wg.Done()
+
+			generation := reservation.Cannon.Generation()
+			shots[i] = Shot{
+				Target:     t.Coordinates,
+				Generation: int(generation),
+			}
+
+			fireResp, err := c.cannonManager.Commit(ctx, reservation, &cannon.FireRequest{
+				Target:  t.Coordinates,
+				Enemies: t.EnemyCount(),
+			})
+			if err != nil {
+				errs[i] = fmt.Errorf("cannon generation %d: %w", generation, err)
+				shots[i].Error = err.Error()
+				return
+			}
+
+			shots[i].Casualties = fireResp.Casualties
+			shots[i].Generation = fireResp.Generation
+		}(i, reservations[i], sel.candidates[i])
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return &SalvoResponse{Shots: shots, Excluded: sel.excluded}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("salvo failed: %w: %w", ErrFireFailed, errors.Join(errs...))
+}
+
+// PlanAttack runs target and cannon selection without firing
+func (c *Coordinator) PlanAttack(ctx context.Context, req *Request) (*Plan, error) {
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	plan, err := c.planAttack(ctx, req)
+	if err != nil {
+		return nil, deadlineError(ctx, err)
+	}
+	return plan, nil
+}
+
+func (c *Coordinator) planAttack(ctx context.Context, req *Request) (*Plan, error) {
+	sel, err := c.scanTargets(req)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedCannon, err := c.cannonManager.GetBestAvailable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannon selection failed: %w", err)
+	}
+
+	if err := sel.choose(req, selectedCannon.BlastRadius()); err != nil {
+		return nil, err
+	}
+
+	return &Plan{
+		Target:     sel.target.Coordinates,
+		Generation: int(selectedCannon.Generation()),
+		Reason:     sel.reason(selectedCannon.Generation()),
+		Score:      sel.score,
+		Excluded:   sel.excluded,
+		Trace:      sel.trace,
+		Scores:     sel.scores,
+	}, nil
+}
+
+// withDeadline bounds ctx by the request timeout
+func (c *Coordinator) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.requestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, c.requestTimeout, ErrRequestTimeout)
+}
+
+// deadlineError marks err as caused by the request running out of its budget
+func deadlineError(ctx context.Context, err error) error {
+	if context.Cause(ctx) == ErrRequestTimeout && !errors.Is(err, ErrRequestTimeout) {
+		return fmt.Errorf("%w: %w", ErrRequestTimeout, err)
+	}
+	return err
+}
+
+// scanTargets builds the protocol chain and converts the scan into the
+// targets the protocols will choose from
+func (c *Coordinator) scanTargets(req *Request) (*selection, error) {
 	// 1. Create protocol chain
 	chain, err := protocol.CreateProtocolChain(req.Protocols)
 	if err != nil {
 		return nil, fmt.Errorf("invalid protocols: %w", err)
 	}
 
-	// 2. Convert scan points to targets
+	// 2. Convert scan points to targets, remembering their scan index and
+	// dropping those in a no-fire zone
+	station := req.station()
 	targets := make([]*target.Target, 0, len(req.Scan))
-	for _, point := range req.Scan {
-		t := target.NewTarget(point.Coordinates, point.Enemies, point.Allies)
-		if t.IsValid() {
-			targets = append(targets, t)
+	indices := make(map[*target.Target]int, len(req.Scan))
+	var excluded []Exclusion
+	for i, point := range req.Scan {
+		t := station.NewTarget(point.Coordinates, point.Enemies, point.Allies)
+		if !t.IsValid() {
+			continue
 		}
+		if zone, ok := c.excludingZone(req, point.Coordinates); ok {
+			excluded = append(excluded, Exclusion{Index: i, Coordinates: point.Coordinates, Zone: zone})
+			continue
+		}
+		targets = append(targets, t)
+		indices[t] = i
 	}
 
 	if len(targets) == 0 {
-		return nil, fmt.Errorf("no valid targets in range")
+		if len(excluded) > 0 {
+			return nil, fmt.Errorf("%w in range outside no-fire zones", target.ErrNoValidTargets)
+		}
+		return nil, fmt.Errorf("%w in range", target.ErrNoValidTargets)
 	}
 
-	// 3. Apply protocol chain to select target
-	selectedTargets, err := protocol.ApplyProtocolChain(chain, targets)
+	return &selection{
+		chain:      chain,
+		mode:       req.mode(),
+		weights:    req.Weights,
+		tieBreaker: c.tieBreakerFor(req),
+		observer:   c.observer,
+		targets:    targets,
+		indices:    indices,
+		inRange:    len(targets),
+		excluded:   excluded,
+		// Allies anywhere in the scan, even out of range, can be caught in the blast
+		allied: alliedPositions(req.Scan),
+	}, nil
+}
+
+// choose applies the protocols as a filter chain or, in score mode, as
+// weighted scores. Collateral damage is judged for a cannon with the given
+// blast radius.
+func (s *selection) choose(req *Request, blastRadius float64) error {
+	s.blastRadius = blastRadius
+	target.AssessCollateral(s.targets, s.allied, blastRadius)
+
+	// 3. Apply protocols to select target
+	observe := protocol.WithObserver(s.observer)
+	var err error
+	switch {
+	case s.mode == ModeScore:
+		err = scoreTargets(req, s, observe)
+	case req.Explain:
+		var steps []protocol.Step
+		s.candidates, steps, err = protocol.ApplyProtocolChainTrace(s.chain, s.targets, observe)
+		s.trace = newTrace(steps, s.indices)
+	default:
+		s.candidates, err = protocol.ApplyProtocolChain(s.chain, s.targets, observe)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("target selection failed: %w", err)
+		return fmt.Errorf("target selection failed: %w", err)
 	}
 
-	// Always select first target after protocol application
-	selectedTarget := selectedTargets[0]
+	// Order tied candidates and select the first
+	if s.mode != ModeScore {
+		s.candidates = protocol.BreakTies(s.tieBreaker, s.candidates)
+	}
+	s.target = s.candidates[0]
+	return nil
+}
 
-	// 4. Get best available cannon
-	selectedCannon, err := c.cannonManager.GetBestAvailable(ctx)
+// rank orders every target the protocols would select, best first, so a
+// salvo can spread its cannons over them. Score mode candidates are already
+// ranked by score. The selection was already reported to the observer by
+// choose, so ranking is not.
+func (s *selection) rank() error {
+	if s.mode == ModeScore {
+		return nil
+	}
+
+	ranked, err := protocol.RankTargets(s.chain, s.targets, s.tieBreaker)
 	if err != nil {
-		return nil, fmt.Errorf("no cannon available: %w", err)
+		return fmt.Errorf("target selection failed: %w", err)
 	}
+	s.candidates = ranked
+	return nil
+}
 
-	// 5. Fire cannon at target
-	fireReq := &cannon.FireRequest{
-		Target:  selectedTarget.Coordinates,
-		Enemies: selectedTarget.Enemies.Number,
+// safeFor reports whether c may fire at the selected target. A cannon with a
+// wider blast radius than selection assumed could hit allies the protocols
+// meant to spare.
+func (s *selection) safeFor(c *cannon.IonCannon) bool {
+	radius := c.BlastRadius()
+	if radius <= s.blastRadius || !protocol.AvoidsCollateral(s.chain) {
+		return true
 	}
+	return !target.AlliesWithin(s.target.Coordinates, s.allied, radius)
+}
 
-	fireResp, err := c.cannonManager.Fire(ctx, selectedCannon, fireReq)
-	if err != nil {
-		return nil, fmt.Errorf("cannon fire failed: %w", err)
+// alliedPositions returns the coordinates of every scan point with allies
+func alliedPositions(scan []ScanPoint) []target.Position {
+	var allied []target.Position
+	for _, point := range scan {
+		if point.Allies != nil && *point.Allies > 0 {
+			allied = append(allied, point.Coordinates)
+		}
 	}
+	return allied
+}
 
-	// 6. Prepare response
-	return &Response{
-		Target:     selectedTarget.Coordinates,
-		Casualties: fireResp.Casualties,
-		Generation: fireResp.Generation,
-	}, nil
+// tieBreakerFor returns the tie-breaker policy of a request
+func (c *Coordinator) tieBreakerFor(req *Request) protocol.TieBreaker {
+	if req.TieBreaker == "" {
+		return c.tieBreaker
+	}
+	return req.TieBreaker
 }
 
-// ValidateRequest checks if the attack request is valid
+// newTrace converts protocol steps into trace steps indexed by scan position
+func newTrace(steps []protocol.Step, indices map[*target.Target]int) []TraceStep {
+	trace := make([]TraceStep, 0, len(steps))
+	for _, step := range steps {
+		trace = append(trace, TraceStep{
+			Protocol:  step.Protocol,
+			Input:     tracedTargets(step.Input, indices),
+			Surviving: tracedTargets(step.Output, indices),
+		})
+	}
+	return trace
+}
+
+// tracedTargets maps targets back to their index in the original scan
+func tracedTargets(targets []*target.Target, indices map[*target.Target]int) []TracedTarget {
+	result := make([]TracedTarget, 0, len(targets))
+	for _, t := range targets {
+		result = append(result, TracedTarget{
+			Index:       indices[t],
+			Coordinates: t.Coordinates,
+		})
+	}
+	return result
+}
+
+// reason explains in plain words why the target and cannon were chosen
+func (s *selection) reason(generation cannon.Generation) string {
+	where := "in range"
+	if len(s.excluded) > 0 {
+		where = "in range and outside no-fire zones"
+	}
+
+	if s.mode == ModeScore {
+		return fmt.Sprintf(
+			"highest score %g of %d scan points %s under weighted protocols [%s]; generation %d is the highest priority cannon available",
+			*s.score, s.inRange, where, weightedNames(s.chain, s.weights), generation,
+		)
+	}
+
+	names := make([]string, 0, len(s.chain))
+	for _, p := range s.chain {
+		names = append(names, p.Name())
+	}
+
+	return fmt.Sprintf(
+		"%d of %d scan points %s survived protocols [%s], first candidate by %s selected; generation %d is the highest priority cannon available",
+		len(s.candidates), s.inRange, where, strings.Join(names, ", "), s.tieBreaker, generation,
+	)
+}
+
+// ValidateRequest checks if the attack request is valid. It reports every
+// invalid field at once as ValidationErrors.
 func ValidateRequest(req *Request) error {
+	var errs ValidationErrors
+
 	if len(req.Protocols) == 0 {
-		return fmt.Errorf("no protocols specified")
+		errs = append(errs, &ValidationError{Field: "protocols", Err: errors.New("no protocols specified")})
+	} else if err := protocol.ValidateProtocols(req.Protocols); err != nil {
+		errs = append(errs, &ValidationError{Field: "protocols", Err: err})
 	}
 
 	if len(req.Scan) == 0 {
-		return fmt.Errorf("no scan points provided")
+		errs = append(errs, &ValidationError{Field: "scan", Err: errors.New("no scan points provided")})
+	}
+
+	errs = append(errs, validateScoring(req)...)
+	errs = append(errs, validateZones(req)...)
+
+	if _, err := protocol.ParseTieBreaker(string(req.TieBreaker)); err != nil {
+		errs = append(errs, &ValidationError{Field: "tie_breaker", Err: err})
+	}
+
+	if req.Station != nil && req.Station.MaxRange < 0 {
+		errs = append(errs, &ValidationError{
+			Field: "station.max_range",
+			Err:   fmt.Errorf("%w: %g", target.ErrInvalidRange, req.Station.MaxRange),
+		})
 	}
 
 	// Validate each scan point
 	for i, point := range req.Scan {
-		if err := validateScanPoint(point); err != nil {
-			return fmt.Errorf("invalid scan point at index %d: %w", i, err)
+		for _, err := range validateScanPoint(point) {
+			err.Field = fmt.Sprintf("scan[%d].%s", i, err.Field)
+			errs = append(errs, err)
 		}
 	}
 
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
-// validateScanPoint checks if a scan point is valid
-func validateScanPoint(point ScanPoint) error {
-	// Validate enemy type
-	switch point.Enemies.Type {
-	case target.EnemyTypeSoldier, target.EnemyTypeMech:
-		// Valid types
-	default:
-		return fmt.Errorf("invalid enemy type: %s", point.Enemies.Type)
+// validateScanPoint checks if a scan point is valid. The returned errors'
+// fields are relative to the scan point.
+func validateScanPoint(point ScanPoint) []*ValidationError {
+	var errs []*ValidationError
+
+	if len(point.Enemies) == 0 {
+		errs = append(errs, &ValidationError{Field: "enemies", Err: target.ErrNoEnemies})
 	}
 
-	// Validate enemy number
-	if point.Enemies.Number <= 0 {
-		return fmt.Errorf("invalid enemy number: %d", point.Enemies.Number)
+	for i, group := range point.Enemies {
+		// Name fields as sent: a single group object has no index
+		field := fmt.Sprintf("enemies[%d]", i)
+		if point.singleGroup {
+			field = "enemies"
+		}
+
+		// Validate enemy type
+		switch group.Type {
+		case target.EnemyTypeSoldier, target.EnemyTypeMech:
+			// Valid types
+		default:
+			errs = append(errs, &ValidationError{
+				Field: field + ".type",
+				Err:   fmt.Errorf("%w: %s", target.ErrInvalidEnemyType, group.Type),
+			})
+		}
+
+		// Validate enemy number
+		if group.Number <= 0 {
+			errs = append(errs, &ValidationError{
+				Field: field + ".number",
+				Err:   fmt.Errorf("%w: %d", target.ErrInvalidEnemyNumber, group.Number),
+			})
+		}
 	}
 
 	// Validate allies if present
 	if point.Allies != nil && *point.Allies < 0 {
-		return fmt.Errorf("invalid allies number: %d", *point.Allies)
+		errs = append(errs, &ValidationError{
+			Field: "allies",
+			Err:   fmt.Errorf("%w: %d", target.ErrInvalidAllies, *point.Allies),
+		})
 	}
 
-	return nil
+	return errs
 }
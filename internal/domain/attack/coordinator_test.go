@@ -2,9 +2,15 @@ package attack
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/protocol"
 	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
 )
 
@@ -14,16 +20,71 @@ type MockCannonManager struct {
 	bestErr    error
 	fireResp   *cannon.FireResponse
 	fireErr    error
+	fireCalls  int
 }
 
 func (m *MockCannonManager) GetBestAvailable(ctx context.Context) (*cannon.IonCannon, error) {
 	return m.bestCannon, m.bestErr
 }
 
-func (m *MockCannonManager) Fire(ctx context.Context, c *cannon.IonCannon, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+func (m *MockCannonManager) Reserve(ctx context.Context, exclude ...*cannon.IonCannon) (*cannon.Reservation, error) {
+	if m.bestErr != nil {
+		return nil, m.bestErr
+	}
+	return &cannon.Reservation{Cannon: m.bestCannon}, nil
+}
+
+func (m *MockCannonManager) ReserveN(ctx context.Context, n int) ([]*cannon.Reservation, error) {
+	r, err := m.Reserve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []*cannon.Reservation{r}, nil
+}
+
+func (m *MockCannonManager) Release(r *cannon.Reservation) {}
+
+func (m *MockCannonManager) Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+	m.fireCalls++
 	return m.fireResp, m.fireErr
 }
 
+// slowCannonManager blocks every fire until the request context is done
+type slowCannonManager struct {
+	MockCannonManager
+}
+
+func (m *slowCannonManager) Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCoordinator_RequestTimeout(t *testing.T) {
+	manager := &slowCannonManager{
+		MockCannonManager: MockCannonManager{bestCannon: cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil)},
+	}
+	coordinator := NewCoordinator(manager, WithRequestTimeout(20*time.Millisecond))
+
+	req := &Request{
+		Protocols: []string{"closest-enemies"},
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 40},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+		},
+	}
+
+	start := time.Now()
+	_, err := coordinator.ProcessAttack(context.Background(), req)
+	if !errors.Is(err, ErrRequestTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected request timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("ProcessAttack took %v, expected it to stop at the request deadline", elapsed)
+	}
+}
+
 func TestCoordinator_ProcessAttack(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -39,11 +100,11 @@ func TestCoordinator_ProcessAttack(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 40},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 10},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
 					},
 					{
 						Coordinates: target.Position{X: 0, Y: 80},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeMech, Number: 1},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
 					},
 				},
 			},
@@ -61,11 +122,11 @@ func TestCoordinator_ProcessAttack(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 40},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 10},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
 					},
 					{
 						Coordinates: target.Position{X: 0, Y: 80},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeMech, Number: 1},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
 					},
 				},
 			},
@@ -83,12 +144,31 @@ func TestCoordinator_ProcessAttack(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 40},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 10},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
 					},
 				},
 			},
 			wantErr: true,
 		},
+		{
+			name: "station origin brings target into range",
+			request: &Request{
+				Protocols: []string{"closest-enemies"},
+				Station:   &target.Station{Origin: target.Position{X: 0, Y: 100}, MaxRange: 60},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 150},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			mockCannon: &cannon.IonCannon{},
+			mockFireResp: &cannon.FireResponse{
+				Casualties: 10,
+				Generation: 1,
+			},
+			wantErr: false,
+		},
 		{
 			name: "no valid targets",
 			request: &Request{
@@ -96,7 +176,7 @@ func TestCoordinator_ProcessAttack(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 150}, // Beyond range
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 10},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
 					},
 				},
 			},
@@ -137,11 +217,655 @@ func TestCoordinator_ProcessAttack(t *testing.T) {
 	}
 }
 
+func TestCoordinator_ProcessAttack_Explain(t *testing.T) {
+	mockManager := &MockCannonManager{
+		bestCannon: &cannon.IonCannon{},
+		fireResp:   &cannon.FireResponse{Casualties: 20, Generation: 1},
+	}
+
+	req := &Request{
+		Protocols: []string{"closest-enemies", "avoid-mech"},
+		Explain:   true,
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 10},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 150}, // Beyond range
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 5}},
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 30},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 20}},
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 20},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+		},
+	}
+
+	resp, err := NewCoordinator(mockManager).ProcessAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []TraceStep{
+		{
+			Protocol: "avoid-mech",
+			Input: []TracedTarget{
+				{Index: 0, Coordinates: target.Position{X: 0, Y: 10}},
+				{Index: 2, Coordinates: target.Position{X: 0, Y: 30}},
+				{Index: 3, Coordinates: target.Position{X: 0, Y: 20}},
+			},
+			Surviving: []TracedTarget{
+				{Index: 2, Coordinates: target.Position{X: 0, Y: 30}},
+				{Index: 3, Coordinates: target.Position{X: 0, Y: 20}},
+			},
+		},
+		{
+			Protocol: "closest-enemies",
+			Input: []TracedTarget{
+				{Index: 2, Coordinates: target.Position{X: 0, Y: 30}},
+				{Index: 3, Coordinates: target.Position{X: 0, Y: 20}},
+			},
+			Surviving: []TracedTarget{
+				{Index: 3, Coordinates: target.Position{X: 0, Y: 20}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(resp.Trace, want) {
+		t.Errorf("Unexpected trace:\ngot:  %+v\nwant: %+v", resp.Trace, want)
+	}
+
+	// Without explain no trace is returned
+	req.Explain = false
+	resp, err = NewCoordinator(mockManager).ProcessAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Trace != nil {
+		t.Errorf("Expected no trace without explain, got %+v", resp.Trace)
+	}
+}
+
+func TestCoordinator_ProcessAttack_ScoreMode(t *testing.T) {
+	mockManager := &MockCannonManager{
+		bestCannon: &cannon.IonCannon{},
+		fireResp:   &cannon.FireResponse{Casualties: 1, Generation: 1},
+	}
+
+	req := &Request{
+		Protocols: []string{"prioritize-mech", "closest-enemies"},
+		Mode:      ModeScore,
+		Explain:   true,
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 10},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 90},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 40},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
+			},
+		},
+	}
+
+	// A chain would always pick the closest mech; scoring trades type against distance
+	resp, err := NewCoordinator(mockManager).ProcessAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Target != (target.Position{X: 0, Y: 40}) {
+		t.Errorf("Expected target (0,40), got %+v", resp.Target)
+	}
+	if resp.Score == nil || *resp.Score != 1.6 {
+		t.Errorf("Expected score 1.6, got %v", resp.Score)
+	}
+	if len(resp.Scores) != 3 || resp.Scores[0].Index != 2 || resp.Scores[2].Index != 0 {
+		t.Errorf("Expected scores ordered by index 2, 1, 0, got %+v", resp.Scores)
+	}
+	if resp.Trace != nil {
+		t.Errorf("Expected no chain trace in score mode, got %+v", resp.Trace)
+	}
+
+	req.Weights = map[string]float64{"prioritize-mech": 0.5, "closest-enemies": 2}
+	resp, err = NewCoordinator(mockManager).ProcessAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Target != (target.Position{X: 0, Y: 10}) {
+		t.Errorf("Expected distance weight to pick (0,10), got %+v", resp.Target)
+	}
+
+	// Chain mode reports no score
+	req.Mode, req.Weights = "", nil
+	resp, err = NewCoordinator(mockManager).ProcessAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Target != (target.Position{X: 0, Y: 40}) || resp.Score != nil || resp.Scores != nil {
+		t.Errorf("Unexpected chain mode response: %+v", resp)
+	}
+}
+
+func TestCoordinator_ProcessAttack_ScoreMode_Validation(t *testing.T) {
+	mockManager := &MockCannonManager{
+		bestCannon: &cannon.IonCannon{},
+		fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+	}
+
+	allies := 2
+	req := &Request{
+		Protocols: []string{"avoid-crossfire", "prioritize-mech", "closest-enemies"},
+		Mode:      ModeScore,
+		Explain:   true,
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 5},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
+				Allies:      &allies,
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 90},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+		},
+	}
+
+	// The close mech outscores the soldiers, but a point with allies is never attacked
+	resp, err := NewCoordinator(mockManager).ProcessAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Target != (target.Position{X: 0, Y: 90}) {
+		t.Errorf("Expected target (0,90), got %+v", resp.Target)
+	}
+	if len(resp.Scores) != 1 || resp.Scores[0].Index != 1 {
+		t.Errorf("Expected only the point without allies to be scored, got %+v", resp.Scores)
+	}
+
+	// Nothing is fired at when validation leaves no target
+	req.Scan = req.Scan[:1]
+	if _, err := NewCoordinator(mockManager).ProcessAttack(context.Background(), req); !errors.Is(err, target.ErrNoValidTargets) {
+		t.Errorf("Expected ErrNoValidTargets, got %v", err)
+	}
+}
+
+func TestCoordinator_ProcessAttack_MixedEnemies(t *testing.T) {
+	manager := &fleetCannonManager{
+		cannons: []*cannon.IonCannon{cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil)},
+		fired:   make(map[cannon.Generation]target.Position),
+	}
+
+	req := &Request{
+		Protocols: []string{"avoid-mech"},
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 10},
+				Enemies: target.EnemyGroups{
+					{Type: target.EnemyTypeSoldier, Number: 30},
+					{Type: target.EnemyTypeMech, Number: 1},
+				},
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 20},
+				Enemies: target.EnemyGroups{
+					{Type: target.EnemyTypeSoldier, Number: 10},
+					{Type: target.EnemyTypeSoldier, Number: 5},
+				},
+			},
+		},
+	}
+
+	resp, err := NewCoordinator(manager).ProcessAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The first point hides a mech among its soldiers
+	if resp.Target != (target.Position{X: 0, Y: 20}) {
+		t.Errorf("Expected avoid-mech to skip the mixed point, got %+v", resp.Target)
+	}
+	// The fleet manager reports every enemy sent to the cannon as a casualty
+	if resp.Casualties != 15 {
+		t.Errorf("Expected the cannon to be told about 15 enemies, got %d", resp.Casualties)
+	}
+}
+
+func TestCoordinator_PlanAttack_NoFireZones(t *testing.T) {
+	mockManager := &MockCannonManager{bestCannon: cannon.NewIonCannon(cannon.Generation1, "", nil)}
+	scan := []ScanPoint{
+		{
+			Coordinates: target.Position{X: 0, Y: 10},
+			Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+		},
+		{
+			Coordinates: target.Position{X: 20, Y: 20},
+			Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+		},
+		{
+			Coordinates: target.Position{X: 0, Y: 60},
+			Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+		},
+	}
+
+	coordinator := NewCoordinator(mockManager, WithNoFireZones(target.Zone{
+		Name:   "base",
+		Circle: &target.Circle{Center: target.Position{X: 0, Y: 0}, Radius: 15},
+	}))
+
+	req := &Request{
+		Protocols: []string{"closest-enemies"},
+		Scan:      scan,
+		NoFireZones: []target.Zone{
+			{Polygon: []target.Position{{X: 10, Y: 10}, {X: 30, Y: 10}, {X: 30, Y: 30}, {X: 10, Y: 30}}},
+		},
+	}
+
+	plan, err := coordinator.PlanAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if plan.Target != (target.Position{X: 0, Y: 60}) {
+		t.Errorf("Expected the only point outside the zones, got %+v", plan.Target)
+	}
+
+	wantExcluded := []Exclusion{
+		{Index: 0, Coordinates: target.Position{X: 0, Y: 10}, Zone: "base"},
+		{Index: 1, Coordinates: target.Position{X: 20, Y: 20}, Zone: "no_fire_zones[0]"},
+	}
+	if !reflect.DeepEqual(plan.Excluded, wantExcluded) {
+		t.Errorf("Unexpected exclusions:\ngot:  %+v\nwant: %+v", plan.Excluded, wantExcluded)
+	}
+
+	// Zones covering every point leave nothing to attack
+	req.NoFireZones = append(req.NoFireZones, target.Zone{Circle: &target.Circle{Center: target.Position{X: 0, Y: 60}, Radius: 1}})
+	if _, err := coordinator.PlanAttack(context.Background(), req); !errors.Is(err, target.ErrNoValidTargets) {
+		t.Errorf("Expected ErrNoValidTargets, got %v", err)
+	}
+}
+
+func TestCoordinator_PlanAttack_BlastRadius(t *testing.T) {
+	allies := 3
+	req := &Request{
+		Protocols: []string{"avoid-crossfire", "closest-enemies"},
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 10},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 50},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+			{
+				// Allies out of range still count
+				Coordinates: target.Position{X: 0, Y: -95},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 1}},
+				Allies:      &allies,
+			},
+			{
+				Coordinates: target.Position{X: 8, Y: 10},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 1}},
+				Allies:      &allies,
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		radius float64
+		want   target.Position
+	}{
+		{name: "no blast radius", radius: 0, want: target.Position{X: 0, Y: 10}},
+		{name: "allies inside blast radius", radius: 10, want: target.Position{X: 0, Y: 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockManager := &MockCannonManager{
+				bestCannon: cannon.NewIonCannon(cannon.Generation1, "", nil, cannon.WithBlastRadius(tt.radius)),
+			}
+
+			plan, err := NewCoordinator(mockManager).PlanAttack(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if plan.Target != tt.want {
+				t.Errorf("Expected target %+v, got %+v", tt.want, plan.Target)
+			}
+		})
+	}
+
+	// Without avoid-crossfire the blast radius does not matter
+	req.Protocols = []string{"closest-enemies"}
+	mockManager := &MockCannonManager{
+		bestCannon: cannon.NewIonCannon(cannon.Generation1, "", nil, cannon.WithBlastRadius(10)),
+	}
+	plan, err := NewCoordinator(mockManager).PlanAttack(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if plan.Target != (target.Position{X: 0, Y: 10}) {
+		t.Errorf("Expected target (0,10), got %+v", plan.Target)
+	}
+}
+
+func TestCoordinator_PlanAttack_TieBreaker(t *testing.T) {
+	mockManager := &MockCannonManager{bestCannon: cannon.NewIonCannon(cannon.Generation1, "", nil)}
+
+	// Both points are 50 km away and tie under closest-enemies
+	req := &Request{
+		Protocols: []string{"closest-enemies"},
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 50},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+			{
+				Coordinates: target.Position{X: 50, Y: 0},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 30}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		coordinator protocol.TieBreaker
+		request     protocol.TieBreaker
+		want        target.Position
+	}{
+		{"default scan order", "", "", target.Position{X: 0, Y: 50}},
+		{"coordinator policy", protocol.TieBreakMostEnemies, "", target.Position{X: 50, Y: 0}},
+		{"request overrides coordinator", protocol.TieBreakMostEnemies, protocol.TieBreakScanOrder, target.Position{X: 0, Y: 50}},
+		{"request policy", "", protocol.TieBreakLowestAngle, target.Position{X: 50, Y: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.coordinator != "" {
+				opts = append(opts, WithTieBreaker(tt.coordinator))
+			}
+			req.TieBreaker = tt.request
+
+			// Identical scans must yield identical targets
+			for range 20 {
+				plan, err := NewCoordinator(mockManager, opts...).PlanAttack(context.Background(), req)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if plan.Target != tt.want {
+					t.Fatalf("Expected target %+v, got %+v", tt.want, plan.Target)
+				}
+			}
+		})
+	}
+}
+
+// fleetCannonManager manages several cannons and fails fire for selected generations
+type fleetCannonManager struct {
+	cannons []*cannon.IonCannon
+	failGen map[cannon.Generation]bool
+	mu      sync.Mutex
+	fired   map[cannon.Generation]target.Position
+}
+
+func (m *fleetCannonManager) GetBestAvailable(ctx context.Context) (*cannon.IonCannon, error) {
+	return m.cannons[0], nil
+}
+
+func (m *fleetCannonManager) Reserve(ctx context.Context, exclude ...*cannon.IonCannon) (*cannon.Reservation, error) {
+	for _, c := range m.cannons {
+		if !slices.Contains(exclude, c) {
+			return &cannon.Reservation{Cannon: c}, nil
+		}
+	}
+	return nil, errors.New("no cannons available")
+}
+
+func (m *fleetCannonManager) ReserveN(ctx context.Context, n int) ([]*cannon.Reservation, error) {
+	var reservations []*cannon.Reservation
+	for _, c := range m.cannons[:min(n, len(m.cannons))] {
+		reservations = append(reservations, &cannon.Reservation{Cannon: c})
+	}
+	return reservations, nil
+}
+
+func (m *fleetCannonManager) Release(r *cannon.Reservation) {}
+
+func (m *fleetCannonManager) Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+	c := r.Cannon
+	if m.failGen[c.Generation()] {
+		return nil, errors.New("cannon busy")
+	}
+
+	m.mu.Lock()
+	m.fired[c.Generation()] = req.Target
+	m.mu.Unlock()
+
+	return &cannon.FireResponse{Casualties: req.Enemies, Generation: int(c.Generation())}, nil
+}
+
+func TestCoordinator_ProcessSalvo(t *testing.T) {
+	scan := []ScanPoint{
+		{
+			Coordinates: target.Position{X: 0, Y: 10},
+			Enemies:     target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
+		},
+		{
+			Coordinates: target.Position{X: 0, Y: 20},
+			Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+		},
+		{
+			Coordinates: target.Position{X: 0, Y: 30},
+			Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 20}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		protocols []string
+		cannons   []cannon.Generation
+		failGen   map[cannon.Generation]bool
+		wantShots []Shot
+		wantErr   bool
+	}{
+		{
+			name:      "more targets than cannons",
+			protocols: []string{"avoid-mech"},
+			cannons:   []cannon.Generation{cannon.Generation1, cannon.Generation3},
+			wantShots: []Shot{
+				{Target: target.Position{X: 0, Y: 20}, Casualties: 10, Generation: 1},
+				{Target: target.Position{X: 0, Y: 30}, Casualties: 20, Generation: 3},
+			},
+		},
+		{
+			name:      "partial failure",
+			protocols: []string{"avoid-mech"},
+			cannons:   []cannon.Generation{cannon.Generation1, cannon.Generation2, cannon.Generation3},
+			failGen:   map[cannon.Generation]bool{cannon.Generation1: true},
+			wantShots: []Shot{
+				{Target: target.Position{X: 0, Y: 20}, Generation: 1, Error: "cannon busy"},
+				{Target: target.Position{X: 0, Y: 30}, Casualties: 20, Generation: 2},
+			},
+		},
+		{
+			name:      "ranked by position",
+			protocols: []string{"closest-enemies"},
+			cannons:   []cannon.Generation{cannon.Generation1, cannon.Generation2, cannon.Generation3},
+			wantShots: []Shot{
+				{Target: target.Position{X: 0, Y: 10}, Casualties: 1, Generation: 1},
+				{Target: target.Position{X: 0, Y: 20}, Casualties: 10, Generation: 2},
+				{Target: target.Position{X: 0, Y: 30}, Casualties: 20, Generation: 3},
+			},
+		},
+		{
+			name:      "every shot fails",
+			protocols: []string{"avoid-mech"},
+			cannons:   []cannon.Generation{cannon.Generation2},
+			failGen:   map[cannon.Generation]bool{cannon.Generation2: true},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &fleetCannonManager{
+				failGen: tt.failGen,
+				fired:   make(map[cannon.Generation]target.Position),
+			}
+			for _, gen := range tt.cannons {
+				manager.cannons = append(manager.cannons, cannon.NewIonCannon(gen, "http://cannon", nil))
+			}
+
+			req := &Request{Protocols: tt.protocols, Scan: scan}
+			resp, err := NewCoordinator(manager).ProcessSalvo(context.Background(), req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Coordinator.ProcessSalvo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(resp.Shots, tt.wantShots) {
+				t.Errorf("Unexpected shots:\ngot:  %+v\nwant: %+v", resp.Shots, tt.wantShots)
+			}
+		})
+	}
+}
+
+// countingObserver counts the targets reported per protocol combination
+type countingObserver struct {
+	mu       sync.Mutex
+	selected map[string]int
+}
+
+func (o *countingObserver) TargetsSelected(protocols string, targets []*target.Target) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.selected[protocols] += len(targets)
+}
+
+func (o *countingObserver) ProtocolApplied(string, time.Duration, []*target.Target, []*target.Target) {
+}
+
+func TestCoordinator_ProcessSalvo_ProtocolObserver(t *testing.T) {
+	manager := &fleetCannonManager{fired: make(map[cannon.Generation]target.Position)}
+	for _, gen := range []cannon.Generation{cannon.Generation1, cannon.Generation2} {
+		manager.cannons = append(manager.cannons, cannon.NewIonCannon(gen, "http://cannon", nil))
+	}
+	observer := &countingObserver{selected: make(map[string]int)}
+	coordinator := NewCoordinator(manager, WithProtocolObserver(observer))
+
+	req := &Request{
+		Protocols: []string{"closest-enemies"},
+		Scan: []ScanPoint{
+			{Coordinates: target.Position{X: 0, Y: 10}, Enemies: target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}}},
+			{Coordinates: target.Position{X: 0, Y: 20}, Enemies: target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 20}}},
+			{Coordinates: target.Position{X: 0, Y: 30}, Enemies: target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}}},
+		},
+	}
+	if _, err := coordinator.ProcessSalvo(context.Background(), req); err != nil {
+		t.Fatalf("Coordinator.ProcessSalvo() error = %v", err)
+	}
+
+	// Ranking the salvo re-applies the chain, but each target is reported once
+	if want := map[string]int{"closest-enemies": 3}; !reflect.DeepEqual(observer.selected, want) {
+		t.Errorf("Observed targets %v, want %v", observer.selected, want)
+	}
+}
+
+func TestCoordinator_PlanAttack(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    *Request
+		bestCannon *cannon.IonCannon
+		bestErr    error
+		wantTarget target.Position
+		wantGen    int
+		wantErr    bool
+	}{
+		{
+			name: "plans closest target",
+			request: &Request{
+				Protocols: []string{"closest-enemies"},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+					{
+						Coordinates: target.Position{X: 0, Y: 20},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
+					},
+				},
+			},
+			bestCannon: cannon.NewIonCannon(cannon.Generation2, "http://cannon2", nil),
+			wantTarget: target.Position{X: 0, Y: 20},
+			wantGen:    2,
+		},
+		{
+			name: "no cannon available",
+			request: &Request{
+				Protocols: []string{"avoid-mech"},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			bestErr: errors.New("no cannons available"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockManager := &MockCannonManager{
+				bestCannon: tt.bestCannon,
+				bestErr:    tt.bestErr,
+			}
+
+			coordinator := NewCoordinator(mockManager)
+			plan, err := coordinator.PlanAttack(context.Background(), tt.request)
+
+			if mockManager.fireCalls != 0 {
+				t.Errorf("PlanAttack() fired %d times, want 0", mockManager.fireCalls)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Coordinator.PlanAttack() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				if plan.Target != tt.wantTarget {
+					t.Errorf("Expected target %+v, got %+v", tt.wantTarget, plan.Target)
+				}
+				if plan.Generation != tt.wantGen {
+					t.Errorf("Expected generation %d, got %d", tt.wantGen, plan.Generation)
+				}
+				if plan.Reason == "" {
+					t.Error("Expected a reason for the plan")
+				}
+			}
+		})
+	}
+}
+
 func TestValidateRequest(t *testing.T) {
 	tests := []struct {
-		name    string
-		request *Request
-		wantErr bool
+		name      string
+		request   *Request
+		wantErr   bool
+		wantField string
 	}{
 		{
 			name: "valid request",
@@ -150,7 +874,7 @@ func TestValidateRequest(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 40},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 10},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
 					},
 				},
 			},
@@ -163,7 +887,20 @@ func TestValidateRequest(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 40},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 10},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown protocol",
+			request: &Request{
+				Protocols: []string{"avoid-mech", "invalid-protocol"},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
 					},
 				},
 			},
@@ -175,7 +912,8 @@ func TestValidateRequest(t *testing.T) {
 				Protocols: []string{"avoid-mech"},
 				Scan:      []ScanPoint{},
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantField: "scan",
 		},
 		{
 			name: "invalid enemy type",
@@ -184,11 +922,12 @@ func TestValidateRequest(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 40},
-						Enemies:     target.EnemyGroup{Type: "invalid", Number: 10},
+						Enemies:     target.EnemyGroups{{Type: "invalid", Number: 10}},
 					},
 				},
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantField: "scan[0].enemies[0].type",
 		},
 		{
 			name: "invalid enemy number",
@@ -197,11 +936,27 @@ func TestValidateRequest(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 40},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 0},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 0}},
 					},
 				},
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantField: "scan[0].enemies[0].number",
+		},
+		{
+			name: "negative station range",
+			request: &Request{
+				Protocols: []string{"avoid-mech"},
+				Station:   &target.Station{MaxRange: -1},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "station.max_range",
 		},
 		{
 			name: "invalid allies number",
@@ -210,12 +965,136 @@ func TestValidateRequest(t *testing.T) {
 				Scan: []ScanPoint{
 					{
 						Coordinates: target.Position{X: 0, Y: 40},
-						Enemies:     target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 10},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
 						Allies:      func() *int { n := -1; return &n }(),
 					},
 				},
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantField: "scan[0].allies",
+		},
+		{
+			name: "unknown mode",
+			request: &Request{
+				Protocols: []string{"avoid-mech"},
+				Mode:      "vote",
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "mode",
+		},
+		{
+			name: "weights without score mode",
+			request: &Request{
+				Protocols: []string{"avoid-mech"},
+				Weights:   map[string]float64{"avoid-mech": 2},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "weights",
+		},
+		{
+			name: "weight for unlisted protocol",
+			request: &Request{
+				Protocols: []string{"avoid-mech"},
+				Mode:      ModeScore,
+				Weights:   map[string]float64{"closest-enemies": 2},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "weights.closest-enemies",
+		},
+		{
+			name: "weight for validation protocol",
+			request: &Request{
+				Protocols: []string{"avoid-crossfire", "closest-enemies"},
+				Mode:      ModeScore,
+				Weights:   map[string]float64{"avoid-crossfire": 2},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "weights.avoid-crossfire",
+		},
+		{
+			name: "unknown tie breaker",
+			request: &Request{
+				Protocols:  []string{"closest-enemies"},
+				TieBreaker: "coin-flip",
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "tie_breaker",
+		},
+		{
+			name: "no enemy groups",
+			request: &Request{
+				Protocols: []string{"avoid-mech"},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "scan[0].enemies",
+		},
+		{
+			name: "invalid second enemy group",
+			request: &Request{
+				Protocols: []string{"avoid-mech"},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies: target.EnemyGroups{
+							{Type: target.EnemyTypeSoldier, Number: 10},
+							{Type: target.EnemyTypeMech, Number: 0},
+						},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "scan[0].enemies[1].number",
+		},
+		{
+			name: "invalid no-fire zone",
+			request: &Request{
+				Protocols:   []string{"avoid-mech"},
+				NoFireZones: []target.Zone{{Circle: &target.Circle{Radius: 5}}, {Name: "shapeless"}},
+				Scan: []ScanPoint{
+					{
+						Coordinates: target.Position{X: 0, Y: 40},
+						Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+					},
+				},
+			},
+			wantErr:   true,
+			wantField: "no_fire_zones[1]",
 		},
 	}
 
@@ -225,6 +1104,13 @@ func TestValidateRequest(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateRequest() error = %v, wantErr %v", err, tt.wantErr)
 			}
+
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+					t.Errorf("ValidateRequest() error = %v, want validation error on %s", err, tt.wantField)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,53 @@
+package attack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrFireFailed is returned when no cannon managed to fire at the selected target
+	ErrFireFailed = errors.New("cannon fire failed")
+	// ErrRequestTimeout is returned when a request runs out of its total time budget
+	ErrRequestTimeout = errors.New("request deadline exceeded")
+)
+
+// ValidationError reports an attack request field that failed validation
+type ValidationError struct {
+	// Field is the path of the offending field, e.g. "scan[3].enemies[0].number"
+	Field string
+	Err   error
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors collects every invalid field of an attack request
+type ValidationErrors []*ValidationError
+
+// Error implements error
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual field errors so errors.Is and errors.As
+// can match any of them
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
@@ -0,0 +1,75 @@
+package attack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+)
+
+// DefaultMaxAttempts is how many cannons an attack tries before giving up
+const DefaultMaxAttempts = 3
+
+// Attempt records a cannon that was tried while firing at the selected target
+type Attempt struct {
+	Generation int    `json:"generation"`
+	Error      string `json:"error,omitempty"`
+}
+
+// fire commits the reservation and, when the cannon fails, retries the same
+// target on the next-best available cannon until one succeeds, the attempt
+// budget is spent or the request context is done. Failover skips cannons
+// that safe rejects.
+func (c *Coordinator) fire(ctx context.Context, reservation *cannon.Reservation, req *cannon.FireRequest, safe func(*cannon.IonCannon) bool) (*cannon.FireResponse, []Attempt, error) {
+	var (
+		attempts []Attempt
+		tried    []*cannon.IonCannon
+		errs     []error
+	)
+
+	for {
+		tried = append(tried, reservation.Cannon)
+		attempt := Attempt{Generation: int(reservation.Cannon.Generation())}
+
+		resp, err := c.cannonManager.Commit(ctx, reservation, req)
+		if err == nil {
+			attempts = append(attempts, attempt)
+			return resp, attempts, nil
+		}
+
+		attempt.Error = err.Error()
+		attempts = append(attempts, attempt)
+		errs = append(errs, fmt.Errorf("cannon generation %d: %w", attempt.Generation, err))
+
+		if len(attempts) >= c.maxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		next, err := c.reserveSafe(ctx, tried, safe)
+		if err != nil {
+			break
+		}
+		reservation = next
+	}
+
+	return nil, attempts, fmt.Errorf("%w: %w", ErrFireFailed, errors.Join(errs...))
+}
+
+// reserveSafe reserves the best cannon not yet tried that safe accepts
+func (c *Coordinator) reserveSafe(ctx context.Context, tried []*cannon.IonCannon, safe func(*cannon.IonCannon) bool) (*cannon.Reservation, error) {
+	skipped := slices.Clone(tried)
+	for {
+		r, err := c.cannonManager.Reserve(ctx, skipped...)
+		if err != nil {
+			return nil, err
+		}
+		if safe(r.Cannon) {
+			return r, nil
+		}
+
+		c.cannonManager.Release(r)
+		skipped = append(skipped, r.Cannon)
+	}
+}
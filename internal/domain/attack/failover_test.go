@@ -0,0 +1,159 @@
+package attack
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+func TestCoordinator_ProcessAttack_Failover(t *testing.T) {
+	request := &Request{
+		Protocols: []string{"closest-enemies"},
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 20},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		failGen      map[cannon.Generation]bool
+		opts         []Option
+		wantGen      int
+		wantAttempts []Attempt
+		wantErr      bool
+	}{
+		{
+			name:    "first cannon succeeds",
+			wantGen: 1,
+		},
+		{
+			name:    "fails over to next cannon",
+			failGen: map[cannon.Generation]bool{cannon.Generation1: true},
+			wantGen: 2,
+			wantAttempts: []Attempt{
+				{Generation: 1, Error: "cannon busy"},
+				{Generation: 2},
+			},
+		},
+		{
+			name:    "every cannon fails",
+			failGen: map[cannon.Generation]bool{cannon.Generation1: true, cannon.Generation2: true, cannon.Generation3: true},
+			wantErr: true,
+		},
+		{
+			name:    "failover disabled",
+			failGen: map[cannon.Generation]bool{cannon.Generation1: true},
+			opts:    []Option{WithMaxAttempts(1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &fleetCannonManager{
+				cannons: []*cannon.IonCannon{
+					cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+					cannon.NewIonCannon(cannon.Generation2, "http://cannon2", nil),
+					cannon.NewIonCannon(cannon.Generation3, "http://cannon3", nil),
+				},
+				failGen: tt.failGen,
+				fired:   make(map[cannon.Generation]target.Position),
+			}
+
+			resp, err := NewCoordinator(manager, tt.opts...).ProcessAttack(context.Background(), request)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Coordinator.ProcessAttack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if resp.Generation != tt.wantGen {
+				t.Errorf("Expected generation %d, got %d", tt.wantGen, resp.Generation)
+			}
+			if !reflect.DeepEqual(resp.Attempts, tt.wantAttempts) {
+				t.Errorf("Unexpected attempts:\ngot:  %+v\nwant: %+v", resp.Attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestCoordinator_ProcessAttack_FailoverStopsOnCancel(t *testing.T) {
+	manager := &fleetCannonManager{
+		cannons: []*cannon.IonCannon{
+			cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+			cannon.NewIonCannon(cannon.Generation2, "http://cannon2", nil),
+		},
+		failGen: map[cannon.Generation]bool{cannon.Generation1: true},
+		fired:   make(map[cannon.Generation]target.Position),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	request := &Request{
+		Protocols: []string{"closest-enemies"},
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 20},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+		},
+	}
+
+	if _, err := NewCoordinator(manager).ProcessAttack(ctx, request); err == nil {
+		t.Fatal("Expected error when the request context is done")
+	}
+
+	if len(manager.fired) != 0 {
+		t.Errorf("Expected no failover after cancellation, fired %v", manager.fired)
+	}
+}
+
+func TestCoordinator_ProcessAttack_FailoverSkipsWiderBlast(t *testing.T) {
+	manager := &fleetCannonManager{
+		cannons: []*cannon.IonCannon{
+			cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+			cannon.NewIonCannon(cannon.Generation2, "http://cannon2", nil, cannon.WithBlastRadius(15)),
+			cannon.NewIonCannon(cannon.Generation3, "http://cannon3", nil, cannon.WithBlastRadius(5)),
+		},
+		failGen: map[cannon.Generation]bool{cannon.Generation1: true},
+		fired:   make(map[cannon.Generation]target.Position),
+	}
+
+	allies := 2
+	request := &Request{
+		Protocols: []string{"avoid-crossfire", "closest-enemies"},
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 10},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+			{
+				Coordinates: target.Position{X: 0, Y: 20},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 5}},
+				Allies:      &allies,
+			},
+		},
+	}
+
+	resp, err := NewCoordinator(manager).ProcessAttack(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Generation 2 would catch the allies 10 km away in its blast
+	wantAttempts := []Attempt{
+		{Generation: 1, Error: "cannon busy"},
+		{Generation: 3},
+	}
+	if !reflect.DeepEqual(resp.Attempts, wantAttempts) {
+		t.Errorf("Unexpected attempts:\ngot:  %+v\nwant: %+v", resp.Attempts, wantAttempts)
+	}
+}
@@ -0,0 +1,310 @@
+package attack
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Default limits of the asynchronous job store
+const (
+	DefaultMaxJobs = 1000
+	DefaultJobTTL  = 5 * time.Minute
+)
+
+var (
+	// ErrJobNotFound is returned for unknown job IDs, including jobs evicted after their TTL
+	ErrJobNotFound = errors.New("attack job not found")
+	// ErrTooManyJobs is returned when the job store is full of unfinished jobs
+	ErrTooManyJobs = errors.New("too many attack jobs in progress")
+	// ErrShuttingDown is returned for jobs submitted after shutdown began
+	ErrShuttingDown = errors.New("job runner shutting down")
+)
+
+// JobStatus is the stage an asynchronous attack has reached
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobSelecting JobStatus = "selecting"
+	JobFiring    JobStatus = "firing"
+	JobDone      JobStatus = "done"
+	JobFailed    JobStatus = "failed"
+)
+
+// Finished reports whether the job has reached a final status
+func (s JobStatus) Finished() bool {
+	return s == JobDone || s == JobFailed
+}
+
+// Job is an attack running in the background. Result is set once the job is
+// done and Err once it has failed.
+type Job struct {
+	ID        string    `json:"id"`
+	Status    JobStatus `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Result    *Response `json:"result,omitempty"`
+	Err       error     `json:"-"`
+}
+
+// JobStore keeps jobs in memory. It holds at most capacity jobs, evicting
+// the oldest finished job to make room, and forgets finished jobs ttl after
+// they finish.
+type JobStore struct {
+	mu       sync.Mutex
+	jobs     map[string]*Job
+	order    []string
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewJobStore creates a job store; non-positive limits fall back to the defaults
+func NewJobStore(capacity int, ttl time.Duration) *JobStore {
+	if capacity <= 0 {
+		capacity = DefaultMaxJobs
+	}
+	if ttl <= 0 {
+		ttl = DefaultJobTTL
+	}
+	return &JobStore{
+		jobs:     make(map[string]*Job),
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// Create adds a new queued job
+func (s *JobStore) Create() (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	if len(s.jobs) >= s.capacity && !s.evictOldestFinished() {
+		return Job{}, ErrTooManyJobs
+	}
+
+	now := s.now()
+	job := &Job{
+		ID:        newJobID(),
+		Status:    JobQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.jobs[job.ID] = job
+	s.order = append(s.order, job.ID)
+	return *job, nil
+}
+
+// Get returns a snapshot of the job with the given ID
+func (s *JobStore) Get(id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("job %s: %w", id, ErrJobNotFound)
+	}
+	return *job, nil
+}
+
+// update moves a job to a new status. Finished jobs are never changed.
+func (s *JobStore) update(id string, status JobStatus, result *Response, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || job.Status.Finished() {
+		return
+	}
+	job.Status = status
+	job.UpdatedAt = s.now()
+	job.Result = result
+	job.Err = err
+}
+
+// evictExpired drops finished jobs older than the TTL; callers must hold s.mu
+func (s *JobStore) evictExpired() {
+	now := s.now()
+	s.order = slices.DeleteFunc(s.order, func(id string) bool {
+		job := s.jobs[id]
+		if job.Status.Finished() && now.Sub(job.UpdatedAt) > s.ttl {
+			delete(s.jobs, id)
+			return true
+		}
+		return false
+	})
+}
+
+// evictOldestFinished drops the oldest finished job and reports whether
+// there was one; callers must hold s.mu
+func (s *JobStore) evictOldestFinished() bool {
+	i := slices.IndexFunc(s.order, func(id string) bool {
+		return s.jobs[id].Status.Finished()
+	})
+	if i < 0 {
+		return false
+	}
+	delete(s.jobs, s.order[i])
+	s.order = slices.Delete(s.order, i, i+1)
+	return true
+}
+
+// newJobID returns a random 128-bit hex identifier
+func newJobID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate job id: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// JobObserver is notified when an attack job finishes, so background attacks
+// can be instrumented like synchronous ones. Implementations must be safe for
+// concurrent use and must not block.
+type JobObserver interface {
+	// JobFinished is called with the job's request, how long it ran and the
+	// error it failed with, if any
+	JobFinished(req *Request, duration time.Duration, err error)
+}
+
+// nopJobObserver discards every event
+type nopJobObserver struct{}
+
+func (nopJobObserver) JobFinished(*Request, time.Duration, error) {}
+
+// JobRunnerOption configures optional JobRunner behaviour
+type JobRunnerOption func(*JobRunner)
+
+// WithJobObserver reports every finished job to o
+func WithJobObserver(o JobObserver) JobRunnerOption {
+	return func(r *JobRunner) {
+		if o != nil {
+			r.observer = o
+		}
+	}
+}
+
+// JobRunner runs attacks in the background and records their progress in a
+// JobStore. At most workers attacks run at once; the rest stay queued.
+type JobRunner struct {
+	coordinator *Coordinator
+	store       *JobStore
+	observer    JobObserver
+	ctx         context.Context
+	slots       chan struct{}
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+	closed      bool
+}
+
+// NewJobRunner creates a job runner. Jobs run under ctx rather than the
+// context of the request that submitted them, so cancelling ctx aborts them.
+func NewJobRunner(ctx context.Context, coordinator *Coordinator, store *JobStore, workers int, opts ...JobRunnerOption) *JobRunner {
+	if workers < 1 {
+		workers = 1
+	}
+	r := &JobRunner{
+		coordinator: coordinator,
+		store:       store,
+		observer:    nopJobObserver{},
+		ctx:         ctx,
+		slots:       make(chan struct{}, workers),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Submit queues an attack and returns its job straight away. The request
+// must already be validated.
+func (r *JobRunner) Submit(req *Request) (Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return Job{}, ErrShuttingDown
+	}
+
+	job, err := r.store.Create()
+	if err != nil {
+		return Job{}, err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(job.ID, req)
+	}()
+
+	return job, nil
+}
+
+// Get returns the current state of a job
+func (r *JobRunner) Get(id string) (Job, error) {
+	return r.store.Get(id)
+}
+
+// Wait blocks until every submitted job has finished
+func (r *JobRunner) Wait() {
+	r.wg.Wait()
+}
+
+// Shutdown stops accepting jobs and waits for the submitted ones to finish,
+// so the outcome of a shot already fired is not lost. It returns ctx's error
+// if ctx ends first; cancelling the runner's context then aborts the rest.
+func (r *JobRunner) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run waits for a free worker slot and processes the attack
+func (r *JobRunner) run(id string, req *Request) {
+	select {
+	case r.slots <- struct{}{}:
+		defer func() { <-r.slots }()
+	case <-r.ctx.Done():
+		r.store.update(id, JobFailed, nil, r.ctx.Err())
+		r.observer.JobFinished(req, 0, r.ctx.Err())
+		return
+	}
+
+	start := time.Now()
+	ctx, cancel := r.coordinator.withDeadline(r.ctx)
+	defer cancel()
+
+	resp, err := r.coordinator.processAttack(ctx, req, func(status JobStatus) {
+		r.store.update(id, status, nil, nil)
+	})
+	if err != nil {
+		err = deadlineError(ctx, err)
+		r.store.update(id, JobFailed, nil, err)
+		r.observer.JobFinished(req, time.Since(start), err)
+		return
+	}
+	r.store.update(id, JobDone, resp, nil)
+	r.observer.JobFinished(req, time.Since(start), nil)
+}
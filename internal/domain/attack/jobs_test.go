@@ -0,0 +1,227 @@
+package attack
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// gatedCannonManager blocks every fire until release is closed
+type gatedCannonManager struct {
+	MockCannonManager
+	firing  chan struct{}
+	release chan struct{}
+}
+
+func (m *gatedCannonManager) Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+	m.firing <- struct{}{}
+	select {
+	case <-m.release:
+		return m.fireResp, m.fireErr
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func jobRequest() *Request {
+	return &Request{
+		Protocols: []string{"closest-enemies"},
+		Scan: []ScanPoint{
+			{
+				Coordinates: target.Position{X: 0, Y: 40},
+				Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+			},
+		},
+	}
+}
+
+// waitForJob polls the runner until the job reaches status
+func waitForJob(t *testing.T, runner *JobRunner, id string, status JobStatus) Job {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		job, err := runner.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", id, err)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Job %s stuck in %s, want %s", id, job.Status, status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestJobRunner(t *testing.T) {
+	manager := &gatedCannonManager{
+		MockCannonManager: MockCannonManager{
+			bestCannon: cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+			fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+		},
+		firing:  make(chan struct{}, 2),
+		release: make(chan struct{}),
+	}
+	runner := NewJobRunner(context.Background(), NewCoordinator(manager), NewJobStore(10, time.Minute), 1)
+
+	first, err := runner.Submit(jobRequest())
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if first.Status != JobQueued || first.ID == "" {
+		t.Errorf("Submit() = %+v, want a queued job with an ID", first)
+	}
+
+	<-manager.firing
+	waitForJob(t, runner, first.ID, JobFiring)
+
+	// The only worker is busy, so the second job waits its turn
+	second, err := runner.Submit(jobRequest())
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if job, _ := runner.Get(second.ID); job.Status != JobQueued {
+		t.Errorf("Second job status = %s, want %s", job.Status, JobQueued)
+	}
+
+	close(manager.release)
+	runner.Wait()
+
+	for _, id := range []string{first.ID, second.ID} {
+		job := waitForJob(t, runner, id, JobDone)
+		if job.Result == nil || job.Result.Casualties != 10 || job.Err != nil {
+			t.Errorf("Job %s = %+v, want a result with 10 casualties", id, job)
+		}
+	}
+}
+
+func TestJobRunner_Failed(t *testing.T) {
+	manager := &MockCannonManager{bestErr: cannon.ErrNoCannonAvailable}
+	runner := NewJobRunner(context.Background(), NewCoordinator(manager), NewJobStore(10, time.Minute), 1)
+
+	job, err := runner.Submit(jobRequest())
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	runner.Wait()
+
+	job = waitForJob(t, runner, job.ID, JobFailed)
+	if !errors.Is(job.Err, cannon.ErrNoCannonAvailable) || job.Result != nil {
+		t.Errorf("Job = %+v, want no result and ErrNoCannonAvailable", job)
+	}
+}
+
+func TestJobRunner_Shutdown(t *testing.T) {
+	manager := &slowCannonManager{
+		MockCannonManager: MockCannonManager{bestCannon: cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil)},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := NewJobRunner(ctx, NewCoordinator(manager), NewJobStore(10, time.Minute), 1)
+
+	job, err := runner.Submit(jobRequest())
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	waitForJob(t, runner, job.ID, JobFiring)
+
+	cancel()
+	runner.Wait()
+
+	job = waitForJob(t, runner, job.ID, JobFailed)
+	if !errors.Is(job.Err, context.Canceled) {
+		t.Errorf("Job error = %v, want context.Canceled", job.Err)
+	}
+}
+
+func TestJobRunner_ShutdownDrains(t *testing.T) {
+	manager := &gatedCannonManager{
+		MockCannonManager: MockCannonManager{
+			bestCannon: cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+			fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+		},
+		firing:  make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	runner := NewJobRunner(context.Background(), NewCoordinator(manager, WithRequestTimeout(0)), NewJobStore(10, time.Minute), 1)
+
+	job, err := runner.Submit(jobRequest())
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-manager.firing
+
+	// A shutdown that runs out of time leaves the job running
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := runner.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	if _, err := runner.Submit(jobRequest()); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Submit() after shutdown error = %v, want ErrShuttingDown", err)
+	}
+
+	// The shot being fired completes and its result is kept
+	done := make(chan error, 1)
+	go func() { done <- runner.Shutdown(context.Background()) }()
+	close(manager.release)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Shutdown() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown() did not return after the job finished")
+	}
+
+	job = waitForJob(t, runner, job.ID, JobDone)
+	if job.Result == nil || job.Result.Casualties != 10 {
+		t.Errorf("Job = %+v, want a result with 10 casualties", job)
+	}
+}
+
+func TestJobStore(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewJobStore(2, time.Minute)
+	store.now = func() time.Time { return now }
+
+	first, _ := store.Create()
+	second, _ := store.Create()
+
+	// Full of unfinished jobs
+	if _, err := store.Create(); !errors.Is(err, ErrTooManyJobs) {
+		t.Fatalf("Create() on a full store error = %v, want ErrTooManyJobs", err)
+	}
+
+	// Finishing a job makes room by evicting it
+	store.update(first.ID, JobDone, &Response{}, nil)
+	third, err := store.Create()
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := store.Get(first.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Get(evicted) error = %v, want ErrJobNotFound", err)
+	}
+
+	// Finished jobs are immutable
+	store.update(second.ID, JobFailed, nil, ErrFireFailed)
+	store.update(second.ID, JobDone, &Response{}, nil)
+	if job, _ := store.Get(second.ID); job.Status != JobFailed {
+		t.Errorf("Finished job status = %s, want %s", job.Status, JobFailed)
+	}
+
+	// Finished jobs expire after the TTL; unfinished ones never do
+	now = now.Add(2 * time.Minute)
+	if _, err := store.Get(second.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Get(expired) error = %v, want ErrJobNotFound", err)
+	}
+	if job, err := store.Get(third.ID); err != nil || job.Status != JobQueued {
+		t.Errorf("Get(unfinished) = %+v, %v, want a queued job", job, err)
+	}
+}
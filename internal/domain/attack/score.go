@@ -0,0 +1,123 @@
+package attack
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/protocol"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// Mode selects how protocols pick a target
+type Mode string
+
+const (
+	// ModeChain applies protocols as filters in tier order and picks the
+	// first survivor. It is the default.
+	ModeChain Mode = "chain"
+	// ModeScore sums the weighted score each protocol gives every target
+	// and picks the highest scoring one
+	ModeScore Mode = "score"
+)
+
+// ScoredTarget reports the score of a scan point, when explain is requested in scoring mode
+type ScoredTarget struct {
+	Index       int             `json:"index"`
+	Coordinates target.Position `json:"coordinates"`
+	Score       float64         `json:"score"`
+}
+
+// mode returns the selection mode of the request
+func (r *Request) mode() Mode {
+	if r.Mode == "" {
+		return ModeChain
+	}
+	return r.Mode
+}
+
+// scoreTargets ranks the targets by weighted protocol score and records the
+// outcome on sel
+func scoreTargets(req *Request, sel *selection, opts ...protocol.ChainOption) error {
+	scored, err := protocol.ScoreTargets(sel.chain, req.Weights, sel.targets, opts...)
+	if err != nil {
+		return err
+	}
+
+	// Order equal scores by the tie-breaker policy
+	slices.SortStableFunc(scored, func(a, b protocol.Scored) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return sel.tieBreaker.Compare(a.Target, b.Target)
+	})
+
+	sel.candidates = make([]*target.Target, 0, len(scored))
+	for _, s := range scored {
+		sel.candidates = append(sel.candidates, s.Target)
+	}
+	sel.score = &scored[0].Score
+
+	if req.Explain {
+		sel.scores = make([]ScoredTarget, 0, len(scored))
+		for _, s := range scored {
+			sel.scores = append(sel.scores, ScoredTarget{
+				Index:       sel.indices[s.Target],
+				Coordinates: s.Target.Coordinates,
+				Score:       s.Score,
+			})
+		}
+	}
+	return nil
+}
+
+// weightedNames lists the protocols with their weights, e.g. "prioritize-mech×2"
+func weightedNames(chain []protocol.Protocol, weights map[string]float64) string {
+	names := make([]string, 0, len(chain))
+	for _, p := range chain {
+		weight, ok := weights[p.Name()]
+		if !ok {
+			weight = 1
+		}
+		names = append(names, fmt.Sprintf("%s×%g", p.Name(), weight))
+	}
+	return strings.Join(names, ", ")
+}
+
+// validateScoring checks the mode and weights of a request
+func validateScoring(req *Request) ValidationErrors {
+	var errs ValidationErrors
+
+	switch req.Mode {
+	case "", ModeChain, ModeScore:
+	default:
+		errs = append(errs, &ValidationError{Field: "mode", Err: fmt.Errorf("unknown mode %q", req.Mode)})
+	}
+
+	if len(req.Weights) > 0 && req.mode() != ModeScore {
+		errs = append(errs, &ValidationError{Field: "weights", Err: fmt.Errorf("weights require mode %q", ModeScore)})
+	}
+
+	names := make([]string, 0, len(req.Weights))
+	for name := range req.Weights {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		switch {
+		case !slices.Contains(req.Protocols, name):
+			errs = append(errs, &ValidationError{
+				Field: "weights." + name,
+				Err:   fmt.Errorf("weight for protocol %s not listed in protocols", name),
+			})
+		case protocol.IsValidation(name):
+			errs = append(errs, &ValidationError{
+				Field: "weights." + name,
+				Err:   fmt.Errorf("validation protocol %s filters targets and cannot be weighted", name),
+			})
+		}
+	}
+
+	return errs
+}
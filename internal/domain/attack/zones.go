@@ -0,0 +1,57 @@
+package attack
+
+import (
+	"fmt"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// Exclusion reports a scan point that a no-fire zone removed before the protocols ran
+type Exclusion struct {
+	Index       int             `json:"index"`
+	Coordinates target.Position `json:"coordinates"`
+	Zone        string          `json:"zone"`
+}
+
+// WithNoFireZones sets no-fire zones that apply to every request, in
+// addition to the ones a request brings
+func WithNoFireZones(zones ...target.Zone) Option {
+	return func(c *Coordinator) {
+		c.noFireZones = zones
+	}
+}
+
+// excludingZone returns the name of the first no-fire zone containing p,
+// checking the coordinator's zones before the request's
+func (c *Coordinator) excludingZone(req *Request, p target.Position) (string, bool) {
+	if name, ok := firstContaining(c.noFireZones, "config", p); ok {
+		return name, true
+	}
+	return firstContaining(req.NoFireZones, "no_fire_zones", p)
+}
+
+// firstContaining returns the name of the first zone containing p. Unnamed
+// zones are named after their source and position, e.g. "no_fire_zones[1]".
+func firstContaining(zones []target.Zone, source string, p target.Position) (string, bool) {
+	for i, z := range zones {
+		if !z.Contains(p) {
+			continue
+		}
+		if z.Name != "" {
+			return z.Name, true
+		}
+		return fmt.Sprintf("%s[%d]", source, i), true
+	}
+	return "", false
+}
+
+// validateZones checks the no-fire zones of a request
+func validateZones(req *Request) ValidationErrors {
+	var errs ValidationErrors
+	for i, z := range req.NoFireZones {
+		if err := z.Validate(); err != nil {
+			errs = append(errs, &ValidationError{Field: fmt.Sprintf("no_fire_zones[%d]", i), Err: err})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,197 @@
+package cannon
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BreakerState represents the state of a cannon's circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen lets one probe call through at a time to test recovery
+	BreakerHalfOpen
+	// BreakerOpen fails requests immediately
+	BreakerOpen
+)
+
+// String returns the breaker state name
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("state(%d)", int(s))
+	}
+}
+
+// BreakerConfig configures a circuit breaker
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before allowing probes
+	OpenTimeout time.Duration
+	// SuccessThreshold is the number of successful probes that closes the breaker.
+	// Probes run one at a time.
+	SuccessThreshold int
+	// OnStateChange is called whenever the breaker changes state.
+	// It runs with the breaker locked and must not call back into it.
+	OnStateChange func(generation Generation, state BreakerState)
+}
+
+// DefaultBreakerConfig returns the breaker configuration used by NewIonCannon
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		OpenTimeout:      5 * time.Second,
+		SuccessThreshold: 1,
+	}
+}
+
+// CircuitBreaker stops calls to a cannon that keeps failing.
+// A nil *CircuitBreaker allows every call.
+type CircuitBreaker struct {
+	generation Generation
+	config     BreakerConfig
+	state      BreakerState
+	failures   int
+	successes  int
+	// probing is set while a half-open probe call is in flight
+	probing  bool
+	openedAt time.Time
+	mu       sync.Mutex
+}
+
+// NewCircuitBreaker creates a closed circuit breaker for a cannon generation
+func NewCircuitBreaker(generation Generation, config BreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = 1
+	}
+	if config.SuccessThreshold < 1 {
+		config.SuccessThreshold = 1
+	}
+
+	return &CircuitBreaker{
+		generation: generation,
+		config:     config,
+	}
+}
+
+// Allow reports whether a call may go through. A half-open breaker lets a
+// single probe through until it is settled, so every allowed call must be
+// followed by RecordSuccess, RecordFailure or RecordAborted.
+func (b *CircuitBreaker) Allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.refresh() {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// State returns the current state, moving an open breaker to half-open
+// once its open timeout has elapsed
+func (b *CircuitBreaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.refresh()
+}
+
+// refresh moves an open breaker to half-open once its open timeout has
+// elapsed and returns the state; callers must hold b.mu
+func (b *CircuitBreaker) refresh() BreakerState {
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		b.transition(BreakerHalfOpen)
+	}
+	return b.state
+}
+
+// RecordSuccess records a successful call
+func (b *CircuitBreaker) RecordSuccess() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if b.state != BreakerHalfOpen {
+		return
+	}
+
+	b.probing = false
+
+	b.successes++
+	if b.successes >= b.config.SuccessThreshold {
+		b.transition(BreakerClosed)
+	}
+}
+
+// RecordFailure records a failed call
+func (b *CircuitBreaker) RecordFailure() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = time.Now()
+		b.transition(BreakerOpen)
+	}
+}
+
+// RecordAborted settles a call that was cut short by its caller and says
+// nothing about the cannon, freeing the probe slot it may hold
+func (b *CircuitBreaker) RecordAborted() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// transition moves the breaker to a new state; callers must hold b.mu
+func (b *CircuitBreaker) transition(state BreakerState) {
+	if b.state == state {
+		return
+	}
+
+	b.state = state
+	b.failures = 0
+	b.successes = 0
+	b.probing = false
+
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.generation, state)
+	}
+}
@@ -0,0 +1,165 @@
+package cannon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var changes []BreakerState
+	b := NewCircuitBreaker(Generation1, BreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      10 * time.Millisecond,
+		SuccessThreshold: 1,
+		OnStateChange: func(generation Generation, state BreakerState) {
+			changes = append(changes, state)
+		},
+	})
+
+	// A success resets the consecutive failure count
+	b.RecordFailure()
+	b.RecordSuccess()
+	b.RecordFailure()
+	if got := b.State(); got != BreakerClosed {
+		t.Fatalf("Expected closed breaker, got %s", got)
+	}
+
+	b.RecordFailure()
+	if got := b.State(); got != BreakerOpen {
+		t.Fatalf("Expected open breaker after threshold, got %s", got)
+	}
+	if b.Allow() {
+		t.Error("Open breaker should not allow calls")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if got := b.State(); got != BreakerHalfOpen {
+		t.Fatalf("Expected half-open breaker after timeout, got %s", got)
+	}
+
+	// A failed probe reopens the breaker
+	b.RecordFailure()
+	if got := b.State(); got != BreakerOpen {
+		t.Fatalf("Expected failed probe to reopen breaker, got %s", got)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("Half-open breaker should allow probes")
+	}
+	b.RecordSuccess()
+	if got := b.State(); got != BreakerClosed {
+		t.Fatalf("Expected successful probe to close breaker, got %s", got)
+	}
+
+	want := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected state changes %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("Expected state changes %v, got %v", want, changes)
+			break
+		}
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbes(t *testing.T) {
+	b := NewCircuitBreaker(Generation1, BreakerConfig{FailureThreshold: 1, SuccessThreshold: 2})
+	b.RecordFailure()
+	if got := b.State(); got != BreakerHalfOpen {
+		t.Fatalf("Expected half-open breaker without an open timeout, got %s", got)
+	}
+
+	// Concurrent callers on a recovering cannon get a single probe
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.Allow() {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := allowed.Load(); got != 1 {
+		t.Fatalf("Half-open breaker allowed %d concurrent calls, want 1", got)
+	}
+
+	// Settling the probe lets the next one through
+	tests := []struct {
+		name   string
+		settle func()
+		want   BreakerState
+	}{
+		{name: "aborted", settle: b.RecordAborted, want: BreakerHalfOpen},
+		{name: "success below threshold", settle: b.RecordSuccess, want: BreakerHalfOpen},
+		{name: "success at threshold", settle: b.RecordSuccess, want: BreakerClosed},
+	}
+	for _, tt := range tests {
+		tt.settle()
+		if got := b.State(); got != tt.want {
+			t.Fatalf("%s: expected %s breaker, got %s", tt.name, tt.want, got)
+		}
+		if !b.Allow() {
+			t.Fatalf("%s: expected the next call to be allowed", tt.name)
+		}
+	}
+
+	// A closed breaker does not limit calls
+	if !b.Allow() {
+		t.Error("Closed breaker should allow concurrent calls")
+	}
+}
+
+func TestIonCannon_CircuitBreaker(t *testing.T) {
+	mockClient := &MockHTTPClient{statusError: errors.New("network error")}
+	c := NewIonCannon(Generation1, "http://cannon1", mockClient, WithCircuitBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+	}))
+
+	if _, err := c.CheckStatus(context.Background()); err == nil {
+		t.Fatal("Expected status check error")
+	}
+	if got := c.BreakerState(); got != BreakerOpen {
+		t.Fatalf("Expected open breaker, got %s", got)
+	}
+
+	// The cannon is skipped without touching the network
+	mockClient.statusError = nil
+	mockClient.statusResponses = map[string]*Status{"http://cannon1": {Generation: 1, Available: true}}
+
+	if _, err := c.CheckStatus(context.Background()); err == nil {
+		t.Error("Expected open breaker to fail status check")
+	}
+
+	manager := NewManager([]*IonCannon{c})
+	if _, err := manager.GetBestAvailable(context.Background()); err == nil {
+		t.Error("Expected manager to skip cannon with open breaker")
+	}
+}
+
+func TestIonCannon_CircuitBreakerIgnoresCanceledCalls(t *testing.T) {
+	mockClient := &MockHTTPClient{statusError: context.Canceled}
+	c := NewIonCannon(Generation1, "http://cannon1", mockClient, WithCircuitBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.CheckStatus(ctx); err == nil {
+		t.Fatal("Expected status check error")
+	}
+	if got := c.BreakerState(); got != BreakerClosed {
+		t.Errorf("Expected canceled call to leave breaker closed, got %s", got)
+	}
+}
@@ -0,0 +1,51 @@
+package cannon
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoCannonAvailable is returned when every cannon is recharging, reserved or down
+	ErrNoCannonAvailable = errors.New("no cannons available")
+	// ErrCannonNotReady is returned when firing a cannon that is still recharging
+	ErrCannonNotReady = errors.New("cannon not ready")
+	// ErrCircuitOpen is returned when a cannon's circuit breaker rejects the call
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+	// ErrCannonReserved is returned when firing a cannon reserved by another request
+	ErrCannonReserved = errors.New("cannon reserved")
+	// ErrReservationExpired is returned when committing a reservation past its lease
+	ErrReservationExpired = errors.New("reservation expired")
+	// ErrUnknownCannon is returned when firing a cannon the manager does not own
+	ErrUnknownCannon = errors.New("invalid cannon")
+	// ErrCannonNotFound is returned when no managed cannon has the requested generation
+	ErrCannonNotFound = errors.New("cannon not found")
+)
+
+// Phase names a step of a cannon call that has its own deadline
+type Phase string
+
+const (
+	// PhaseStatus is a cannon status check
+	PhaseStatus Phase = "status"
+	// PhaseFire is a cannon fire request
+	PhaseFire Phase = "fire"
+)
+
+// PhaseError reports a cannon call that ran out of its phase deadline
+// while the caller's own context was still live
+type PhaseError struct {
+	Generation Generation
+	Phase      Phase
+	Err        error
+}
+
+// Error implements error
+func (e *PhaseError) Error() string {
+	return fmt.Sprintf("cannon generation %d %s timed out: %v", e.Generation, e.Phase, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *PhaseError) Unwrap() error {
+	return e.Err
+}
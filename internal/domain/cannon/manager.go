@@ -2,31 +2,62 @@ package cannon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 )
 
 // Manager handles the coordination of multiple ion cannons
 type Manager struct {
 	cannons []*IonCannon
 	mu      sync.RWMutex
+
+	leaseTimeout time.Duration
+	reservations map[*IonCannon]*Reservation
+	resMu        sync.Mutex
+}
+
+// ManagerOption configures optional Manager behaviour
+type ManagerOption func(*Manager)
+
+// WithLeaseTimeout sets how long a reservation holds a cannon before it expires
+func WithLeaseTimeout(d time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.leaseTimeout = d
+	}
 }
 
 // NewManager creates a new cannon manager
-func NewManager(cannons []*IonCannon) *Manager {
+func NewManager(cannons []*IonCannon, opts ...ManagerOption) *Manager {
 	// Sort cannons by generation to ensure consistent priority
 	sort.Slice(cannons, func(i, j int) bool {
 		return cannons[i].Generation() < cannons[j].Generation()
 	})
 
-	return &Manager{
-		cannons: cannons,
+	m := &Manager{
+		cannons:      cannons,
+		leaseTimeout: DefaultLeaseTimeout,
+		reservations: make(map[*IonCannon]*Reservation),
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 // GetBestAvailable finds the best available cannon based on generation priority
 func (m *Manager) GetBestAvailable(ctx context.Context) (*IonCannon, error) {
+	available, err := m.GetAvailable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return available[0], nil
+}
+
+// GetAvailable returns every available, unreserved cannon ordered by generation priority
+func (m *Manager) GetAvailable(ctx context.Context) ([]*IonCannon, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -45,14 +76,30 @@ func (m *Manager) GetBestAvailable(ctx context.Context) (*IonCannon, error) {
 		go func(cannon *IonCannon) {
 			defer wg.Done()
 
+			// Skip if another request holds it. Checked first, since a
+			// reservation is held until its fire request completes.
+			if m.isReserved(cannon) {
+				results <- result{err: fmt.Errorf("cannon generation %d: %w", cannon.Generation(), ErrCannonReserved)}
+				return
+			}
+
 			// Skip if not available based on fire time
 			if !cannon.IsAvailable() {
-				results <- result{err: fmt.Errorf("cannon generation %d not available", cannon.Generation())}
+				cannon.observer.CannonAvailability(cannon.Generation(), false)
+				results <- result{err: fmt.Errorf("cannon generation %d: %w", cannon.Generation(), ErrCannonNotReady)}
+				return
+			}
+
+			// Skip cannons known to be failing
+			if cannon.BreakerState() == BreakerOpen {
+				cannon.observer.CannonAvailability(cannon.Generation(), false)
+				results <- result{err: fmt.Errorf("cannon generation %d: %w", cannon.Generation(), ErrCircuitOpen)}
 				return
 			}
 
 			// Check HTTP status
 			status, err := cannon.CheckStatus(ctx)
+			cannon.observer.CannonAvailability(cannon.Generation(), err == nil && status.Available)
 			if err != nil {
 				results <- result{err: fmt.Errorf("cannon generation %d status check failed: %w", cannon.Generation(), err)}
 				return
@@ -71,12 +118,12 @@ func (m *Manager) GetBestAvailable(ctx context.Context) (*IonCannon, error) {
 		close(results)
 	}()
 
-	// Collect results and find best available cannon
-	var bestCannon *IonCannon
-	var bestGeneration Generation = 4 // Higher than any valid generation
-
+	// Collect available cannons, keeping why the others could not be checked
+	var available []result
+	var errs []error
 	for r := range results {
 		if r.err != nil {
+			errs = append(errs, r.err)
 			continue
 		}
 
@@ -84,18 +131,31 @@ func (m *Manager) GetBestAvailable(ctx context.Context) (*IonCannon, error) {
 			continue
 		}
 
-		// Update best cannon if this one has lower generation
-		if Generation(r.status.Generation) < bestGeneration {
-			bestCannon = r.cannon
-			bestGeneration = Generation(r.status.Generation)
-		}
+		available = append(available, r)
 	}
 
-	if bestCannon == nil {
-		return nil, fmt.Errorf("no cannons available")
+	if len(available) == 0 {
+		// Report a deadline or cancellation that caused every check to fail
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNoCannonAvailable, err)
+		}
+		// Report why the checks failed, so status timeouts surface as such
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("%w: %w", ErrNoCannonAvailable, errors.Join(errs...))
+		}
+		return nil, ErrNoCannonAvailable
 	}
 
-	return bestCannon, nil
+	// Lower generations have priority
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].status.Generation < available[j].status.Generation
+	})
+
+	cannons := make([]*IonCannon, 0, len(available))
+	for _, r := range available {
+		cannons = append(cannons, r.cannon)
+	}
+	return cannons, nil
 }
 
 // Fire attempts to fire the specified cannon at the target
@@ -104,15 +164,13 @@ func (m *Manager) Fire(ctx context.Context, cannon *IonCannon, req *FireRequest)
 	defer m.mu.RUnlock()
 
 	// Verify cannon is still in our list
-	found := false
-	for _, c := range m.cannons {
-		if c == cannon {
-			found = true
-			break
-		}
+	if !m.owns(cannon) {
+		return nil, ErrUnknownCannon
 	}
-	if !found {
-		return nil, fmt.Errorf("invalid cannon")
+
+	// Refuse cannons reserved by another request
+	if m.isReserved(cannon) {
+		return nil, fmt.Errorf("cannon generation %d: %w", cannon.Generation(), ErrCannonReserved)
 	}
 
 	// Attempt to fire
@@ -124,6 +182,16 @@ func (m *Manager) Fire(ctx context.Context, cannon *IonCannon, req *FireRequest)
 	return resp, nil
 }
 
+// owns reports whether the cannon is managed by m; callers must hold m.mu
+func (m *Manager) owns(cannon *IonCannon) bool {
+	for _, c := range m.cannons {
+		if c == cannon {
+			return true
+		}
+	}
+	return false
+}
+
 // GetStatus returns the current status of all cannons
 func (m *Manager) GetStatus(ctx context.Context) map[Generation]*Status {
 	m.mu.RLock()
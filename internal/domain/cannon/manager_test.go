@@ -128,6 +128,31 @@ func TestManager_GetBestAvailable(t *testing.T) {
 	}
 }
 
+func TestManager_GetAvailable(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		statusResponses: map[string]*Status{
+			"http://cannon1": {Generation: 1, Available: false},
+			"http://cannon2": {Generation: 2, Available: true},
+			"http://cannon3": {Generation: 3, Available: true},
+		},
+	}
+
+	manager := NewManager([]*IonCannon{
+		NewIonCannon(Generation3, "http://cannon3", mockClient),
+		NewIonCannon(Generation1, "http://cannon1", mockClient),
+		NewIonCannon(Generation2, "http://cannon2", mockClient),
+	})
+
+	got, err := manager.GetAvailable(context.Background())
+	if err != nil {
+		t.Fatalf("Manager.GetAvailable() error = %v", err)
+	}
+
+	if len(got) != 2 || got[0].Generation() != Generation2 || got[1].Generation() != Generation3 {
+		t.Errorf("Manager.GetAvailable() returned unexpected cannons: %v", got)
+	}
+}
+
 func TestManager_Fire(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -215,3 +240,55 @@ func TestIonCannon_IsAvailable(t *testing.T) {
 		})
 	}
 }
+
+// slowHTTPClient blocks every call until its context is done
+type slowHTTPClient struct{}
+
+func (slowHTTPClient) GetStatus(ctx context.Context, baseURL string) (*Status, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (slowHTTPClient) Fire(ctx context.Context, baseURL string, req *FireRequest) (*FireResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestIonCannon_PhaseTimeouts(t *testing.T) {
+	observer := &recordingObserver{}
+	cannon := NewIonCannon(Generation2, "http://slow", slowHTTPClient{},
+		WithStatusTimeout(5*time.Millisecond),
+		WithFireTimeout(10*time.Millisecond),
+		WithObserver(observer),
+	)
+
+	_, err := cannon.RefreshStatus(context.Background())
+	var phaseErr *PhaseError
+	if !errors.As(err, &phaseErr) || phaseErr.Phase != PhaseStatus || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected status phase timeout, got %v", err)
+	}
+
+	start := time.Now()
+	_, err = cannon.Fire(context.Background(), &FireRequest{Target: target.Position{X: 0, Y: 10}, Enemies: 1})
+	if !errors.As(err, &phaseErr) || phaseErr.Phase != PhaseFire {
+		t.Errorf("Expected fire phase timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Fire took %v, expected it to stop at the fire deadline", elapsed)
+	}
+
+	if len(observer.timedOut) != 2 {
+		t.Errorf("Expected timeouts reported for both phases, got %v", observer.timedOut)
+	}
+	if _, lastErr := cannon.LastError(); lastErr == nil {
+		t.Error("Expected phase timeouts to count as cannon failures")
+	}
+
+	// A caller deadline shorter than the phase deadline is not the cannon's fault
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = NewIonCannon(Generation3, "http://slow", slowHTTPClient{}).RefreshStatus(ctx)
+	if err == nil || errors.As(err, &phaseErr) {
+		t.Errorf("Expected caller deadline error without phase error, got %v", err)
+	}
+}
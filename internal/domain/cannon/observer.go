@@ -0,0 +1,30 @@
+package cannon
+
+import "time"
+
+// Observer receives cannon events for instrumentation. Implementations must
+// be safe for concurrent use and must not block.
+type Observer interface {
+	// CannonFired is called after every fire request sent to a cannon
+	CannonFired(generation Generation, duration time.Duration, err error)
+	// CannonAvailability is called whenever a cannon's availability is checked
+	CannonAvailability(generation Generation, available bool)
+	// CannonTimedOut is called when a cannon call runs out of its phase deadline
+	CannonTimedOut(generation Generation, phase Phase)
+}
+
+// nopObserver discards every event
+type nopObserver struct{}
+
+func (nopObserver) CannonFired(Generation, time.Duration, error) {}
+func (nopObserver) CannonAvailability(Generation, bool)          {}
+func (nopObserver) CannonTimedOut(Generation, Phase)             {}
+
+// WithObserver reports the cannon's fire, availability and timeout events to o
+func WithObserver(o Observer) CannonOption {
+	return func(c *IonCannon) {
+		if o != nil {
+			c.observer = o
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cannon
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// recordingObserver records every cannon event
+type recordingObserver struct {
+	mu           sync.Mutex
+	fired        []error
+	availability map[Generation]bool
+	timedOut     []Phase
+}
+
+func (o *recordingObserver) CannonFired(generation Generation, duration time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.fired = append(o.fired, err)
+}
+
+func (o *recordingObserver) CannonAvailability(generation Generation, available bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.availability == nil {
+		o.availability = make(map[Generation]bool)
+	}
+	o.availability[generation] = available
+}
+
+func (o *recordingObserver) CannonTimedOut(generation Generation, phase Phase) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.timedOut = append(o.timedOut, phase)
+}
+
+func TestManager_Observer(t *testing.T) {
+	observer := &recordingObserver{}
+	client := &MockHTTPClient{
+		statusResponses: map[string]*Status{
+			"http://cannon1": {Generation: 1, Available: true},
+			"http://cannon2": {Generation: 2, Available: false},
+		},
+		fireResponses: map[string]*FireResponse{
+			"http://cannon1": {Casualties: 10, Generation: 1},
+		},
+	}
+	manager := NewManager([]*IonCannon{
+		NewIonCannon(Generation1, "http://cannon1", client, WithObserver(observer)),
+		NewIonCannon(Generation2, "http://cannon2", client, WithObserver(observer)),
+	})
+
+	best, err := manager.GetBestAvailable(context.Background())
+	if err != nil {
+		t.Fatalf("Manager.GetBestAvailable() error = %v", err)
+	}
+	if !observer.availability[Generation1] || observer.availability[Generation2] {
+		t.Errorf("Unexpected availability reported: %v", observer.availability)
+	}
+
+	if _, err := manager.Fire(context.Background(), best, &FireRequest{Target: target.Position{X: 0, Y: 10}, Enemies: 1}); err != nil {
+		t.Fatalf("Manager.Fire() error = %v", err)
+	}
+	if len(observer.fired) != 1 || observer.fired[0] != nil {
+		t.Errorf("Expected one successful fire reported, got %v", observer.fired)
+	}
+
+	// The poller reports the recharging cannon as unavailable
+	manager.refreshAll(context.Background())
+	if observer.availability[Generation1] {
+		t.Error("Expected recharging cannon to be reported unavailable")
+	}
+}
@@ -0,0 +1,54 @@
+package cannon
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval keeps the 100ms status cache warm between requests
+const DefaultPollInterval = 50 * time.Millisecond
+
+// RunPoller refreshes the status of every cannon on the given interval so
+// requests are served from the cache. It blocks until ctx is done and
+// returns once in-flight refreshes have finished.
+func (m *Manager) RunPoller(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.refreshAll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refreshAll(ctx)
+		}
+	}
+}
+
+// refreshAll refreshes every cannon's status concurrently and reports the
+// resulting availability. Failures are recorded by each cannon's circuit
+// breaker and leave the cached status in place so it can be served stale.
+func (m *Manager) refreshAll(ctx context.Context) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, c := range m.cannons {
+		wg.Add(1)
+		go func(cannon *IonCannon) {
+			defer wg.Done()
+			status, err := cannon.RefreshStatus(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			cannon.observer.CannonAvailability(cannon.Generation(), err == nil && status.Available && cannon.IsAvailable())
+		}(c)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,176 @@
+package cannon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingHTTPClient counts status calls and can be switched to failing.
+// When called is set, every status call is signalled on it without blocking.
+type countingHTTPClient struct {
+	MockHTTPClient
+	mu     sync.Mutex
+	calls  int
+	called chan struct{}
+}
+
+func (c *countingHTTPClient) GetStatus(ctx context.Context, baseURL string) (*Status, error) {
+	c.mu.Lock()
+	c.calls++
+	err := c.statusError
+	c.mu.Unlock()
+
+	select {
+	case c.called <- struct{}{}:
+	default:
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return c.statusResponses[baseURL], nil
+}
+
+func (c *countingHTTPClient) setError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statusError = err
+}
+
+func (c *countingHTTPClient) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// expire ages the cached status past its TTL without waiting for it
+func expire(c *StatusCache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timestamp = c.timestamp.Add(-c.ttl - time.Millisecond)
+}
+
+func TestManager_RunPoller(t *testing.T) {
+	client := &countingHTTPClient{
+		MockHTTPClient: MockHTTPClient{
+			statusResponses: map[string]*Status{"http://cannon1": {Generation: 1, Available: true}},
+		},
+		called: make(chan struct{}),
+	}
+	c := NewIonCannon(Generation1, "http://cannon1", client)
+	manager := NewManager([]*IonCannon{c})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		manager.RunPoller(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	// The poller refreshes once on start and again on every tick
+	for i := 0; i < 2; i++ {
+		select {
+		case <-client.called:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected poller to refresh status repeatedly, got %d calls", client.callCount())
+		}
+	}
+
+	// Requests are served from the cache the poller keeps warm
+	if c.statusCache.Get() == nil {
+		t.Error("Expected poller to populate the status cache")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Poller did not stop after context cancellation")
+	}
+
+	// A poller that kept running would refresh many times over this window
+	select {
+	case <-client.called:
+		t.Error("Poller kept refreshing after it stopped")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestIonCannon_CheckStatus_Stale(t *testing.T) {
+	tests := []struct {
+		name       string
+		staleGrace time.Duration
+		wantErr    bool
+	}{
+		{
+			name:       "within grace window",
+			staleGrace: time.Minute,
+		},
+		{
+			name:    "no grace window",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &countingHTTPClient{
+				MockHTTPClient: MockHTTPClient{
+					statusResponses: map[string]*Status{"http://cannon1": {Generation: 1, Available: true}},
+				},
+			}
+			c := NewIonCannon(Generation1, "http://cannon1", client, WithStaleGrace(tt.staleGrace))
+
+			if _, err := c.CheckStatus(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expire(c.statusCache)
+			client.setError(errors.New("network error"))
+
+			status, err := c.CheckStatus(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IonCannon.CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (status == nil || !status.Available) {
+				t.Errorf("Expected stale available status, got %+v", status)
+			}
+		})
+	}
+}
+
+func TestIonCannon_CheckStatus_StaleAfterFailedRefresh(t *testing.T) {
+	client := &countingHTTPClient{
+		MockHTTPClient: MockHTTPClient{
+			statusResponses: map[string]*Status{"http://cannon1": {Generation: 1, Available: true}},
+		},
+	}
+	c := NewIonCannon(Generation1, "http://cannon1", client, WithStaleGrace(time.Minute))
+
+	if _, err := c.RefreshStatus(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A background refresh fails once the cached status expires
+	expire(c.statusCache)
+	client.setError(errors.New("network error"))
+	if _, err := c.RefreshStatus(context.Background()); err == nil {
+		t.Fatal("Expected refresh error")
+	}
+	calls := client.callCount()
+
+	// Requests are served the stale status without waiting on the cannon
+	status, err := c.CheckStatus(context.Background())
+	if err != nil {
+		t.Fatalf("IonCannon.CheckStatus() error = %v", err)
+	}
+	if status == nil || !status.Available {
+		t.Errorf("Expected stale available status, got %+v", status)
+	}
+	if got := client.callCount(); got != calls {
+		t.Errorf("CheckStatus() made %d status calls, want 0", got-calls)
+	}
+}
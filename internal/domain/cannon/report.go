@@ -0,0 +1,82 @@
+package cannon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Report describes the operational state of a single cannon
+type Report struct {
+	Generation     int        `json:"generation"`
+	Available      bool       `json:"available"`
+	ReadyInSeconds float64    `json:"ready_in_seconds"`
+	Reserved       bool       `json:"reserved"`
+	BreakerState   string     `json:"breaker_state"`
+	BlastRadius    float64    `json:"blast_radius"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastErrorAt    *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Reports returns a report for every cannon ordered by generation
+func (m *Manager) Reports(ctx context.Context) []Report {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	reports := make([]Report, len(m.cannons))
+	var wg sync.WaitGroup
+
+	for i, c := range m.cannons {
+		wg.Add(1)
+		go func(i int, cannon *IonCannon) {
+			defer wg.Done()
+			reports[i] = m.report(ctx, cannon)
+		}(i, c)
+	}
+
+	wg.Wait()
+	return reports
+}
+
+// Report returns the report for the cannon of the given generation
+func (m *Manager) Report(ctx context.Context, generation Generation) (*Report, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, c := range m.cannons {
+		if c.Generation() == generation {
+			report := m.report(ctx, c)
+			return &report, nil
+		}
+	}
+	return nil, fmt.Errorf("generation %d: %w", generation, ErrCannonNotFound)
+}
+
+// report builds the report for a single cannon; callers must hold m.mu
+func (m *Manager) report(ctx context.Context, cannon *IonCannon) Report {
+	readyIn := cannon.ReadyIn()
+	state := cannon.BreakerState()
+
+	available := readyIn == 0 && state != BreakerOpen
+	if available {
+		status, err := cannon.CheckStatus(ctx)
+		available = err == nil && status.Available
+	}
+
+	report := Report{
+		Generation:     int(cannon.Generation()),
+		Available:      available,
+		ReadyInSeconds: readyIn.Seconds(),
+		Reserved:       m.isReserved(cannon),
+		BreakerState:   state.String(),
+		BlastRadius:    cannon.BlastRadius(),
+	}
+
+	if at, err := cannon.LastError(); err != nil {
+		report.LastError = err.Error()
+		report.LastErrorAt = &at
+	}
+
+	return report
+}
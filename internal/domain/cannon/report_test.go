@@ -0,0 +1,61 @@
+package cannon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+func TestManager_Reports(t *testing.T) {
+	manager := newReservationTestManager()
+
+	// Generation 1 recharges after firing and generation 3 is reserved
+	req := &FireRequest{Target: target.Position{X: 0, Y: 10}, Enemies: 10}
+	if _, err := manager.Fire(context.Background(), manager.cannons[0], req); err != nil {
+		t.Fatalf("Manager.Fire() error = %v", err)
+	}
+	r, err := manager.Reserve(context.Background(), manager.cannons[1])
+	if err != nil {
+		t.Fatalf("Manager.Reserve() error = %v", err)
+	}
+	defer manager.Release(r)
+
+	reports := manager.Reports(context.Background())
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 reports, got %d", len(reports))
+	}
+
+	first := reports[0]
+	if first.Generation != 1 || first.Available || first.ReadyInSeconds <= 0 || first.ReadyInSeconds > 3.5 {
+		t.Errorf("Unexpected report for recharging cannon: %+v", first)
+	}
+
+	second := reports[1]
+	if second.Generation != 2 || !second.Available || second.Reserved || second.ReadyInSeconds != 0 {
+		t.Errorf("Unexpected report for idle cannon: %+v", second)
+	}
+
+	third := reports[2]
+	if third.Generation != 3 || !third.Reserved || third.BreakerState != "closed" {
+		t.Errorf("Unexpected report for reserved cannon: %+v", third)
+	}
+}
+
+func TestManager_Report(t *testing.T) {
+	client := &MockHTTPClient{statusError: errors.New("connection refused")}
+	manager := NewManager([]*IonCannon{NewIonCannon(Generation2, "http://cannon2", client)})
+
+	report, err := manager.Report(context.Background(), Generation2)
+	if err != nil {
+		t.Fatalf("Manager.Report() error = %v", err)
+	}
+	if report.Available || report.LastError == "" || report.LastErrorAt == nil {
+		t.Errorf("Expected unreachable cannon to report its last error, got %+v", report)
+	}
+
+	if _, err := manager.Report(context.Background(), Generation3); !errors.Is(err, ErrCannonNotFound) {
+		t.Errorf("Expected ErrCannonNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package cannon
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultLeaseTimeout is how long a reservation holds a cannon by default
+const DefaultLeaseTimeout = time.Second
+
+// Reservation grants a request exclusive use of a cannon until it is
+// committed, released or its lease expires
+type Reservation struct {
+	Cannon    *IonCannon
+	ExpiresAt time.Time
+}
+
+// Reserve atomically claims the best available cannon that no other
+// request holds, skipping any cannon in exclude. The reservation must be
+// committed or released.
+func (m *Manager) Reserve(ctx context.Context, exclude ...*IonCannon) (*Reservation, error) {
+	reservations, err := m.reserve(ctx, 1, exclude)
+	if err != nil {
+		return nil, err
+	}
+	return reservations[0], nil
+}
+
+// ReserveN atomically claims up to n available cannons in generation
+// priority order. It fails only when no cannon could be reserved.
+func (m *Manager) ReserveN(ctx context.Context, n int) ([]*Reservation, error) {
+	return m.reserve(ctx, n, nil)
+}
+
+// reserve claims up to n available cannons that are not in exclude
+func (m *Manager) reserve(ctx context.Context, n int, exclude []*IonCannon) ([]*Reservation, error) {
+	candidates, err := m.GetAvailable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.resMu.Lock()
+	defer m.resMu.Unlock()
+
+	now := time.Now()
+	reservations := make([]*Reservation, 0, n)
+	for _, c := range candidates {
+		if len(reservations) == n {
+			break
+		}
+
+		if slices.Contains(exclude, c) {
+			continue
+		}
+
+		// Another request may have claimed it since the status check
+		if r, ok := m.reservations[c]; ok && now.Before(r.ExpiresAt) {
+			continue
+		}
+
+		r := &Reservation{
+			Cannon:    c,
+			ExpiresAt: now.Add(m.leaseTimeout),
+		}
+		m.reservations[c] = r
+		reservations = append(reservations, r)
+	}
+
+	if len(reservations) == 0 {
+		return nil, ErrNoCannonAvailable
+	}
+
+	return reservations, nil
+}
+
+// Commit fires the reserved cannon and releases the reservation.
+// It fails without firing when the reservation has expired.
+func (m *Manager) Commit(ctx context.Context, r *Reservation, req *FireRequest) (*FireResponse, error) {
+	defer m.Release(r)
+
+	if !m.holds(r) {
+		return nil, fmt.Errorf("cannon generation %d: %w", r.Cannon.Generation(), ErrReservationExpired)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	resp, err := r.Cannon.Fire(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fire failed: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Release gives the cannon back without firing. Releasing an expired or
+// already released reservation is a no-op.
+func (m *Manager) Release(r *Reservation) {
+	if r == nil {
+		return
+	}
+
+	m.resMu.Lock()
+	defer m.resMu.Unlock()
+
+	if m.reservations[r.Cannon] == r {
+		delete(m.reservations, r.Cannon)
+	}
+}
+
+// holds reports whether r is still the live reservation for its cannon
+func (m *Manager) holds(r *Reservation) bool {
+	m.resMu.Lock()
+	defer m.resMu.Unlock()
+
+	return m.reservations[r.Cannon] == r && time.Now().Before(r.ExpiresAt)
+}
+
+// isReserved reports whether the cannon is held by an unexpired reservation
+func (m *Manager) isReserved(c *IonCannon) bool {
+	m.resMu.Lock()
+	defer m.resMu.Unlock()
+
+	r, ok := m.reservations[c]
+	return ok && time.Now().Before(r.ExpiresAt)
+}
@@ -0,0 +1,218 @@
+package cannon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+func newReservationTestManager(opts ...ManagerOption) *Manager {
+	mockClient := &MockHTTPClient{
+		statusResponses: map[string]*Status{
+			"http://cannon1": {Generation: 1, Available: true},
+			"http://cannon2": {Generation: 2, Available: true},
+			"http://cannon3": {Generation: 3, Available: true},
+		},
+		fireResponses: map[string]*FireResponse{
+			"http://cannon1": {Casualties: 10, Generation: 1},
+			"http://cannon2": {Casualties: 10, Generation: 2},
+			"http://cannon3": {Casualties: 10, Generation: 3},
+		},
+	}
+
+	return NewManager([]*IonCannon{
+		NewIonCannon(Generation1, "http://cannon1", mockClient),
+		NewIonCannon(Generation2, "http://cannon2", mockClient),
+		NewIonCannon(Generation3, "http://cannon3", mockClient),
+	}, opts...)
+}
+
+func TestManager_Reserve_Concurrent(t *testing.T) {
+	manager := newReservationTestManager()
+
+	var wg sync.WaitGroup
+	reservations := make([]*Reservation, 3)
+	errs := make([]error, 3)
+	for i := range reservations {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reservations[i], errs[i] = manager.Reserve(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[Generation]bool)
+	for i, r := range reservations {
+		if errs[i] != nil {
+			t.Fatalf("Manager.Reserve() error = %v", errs[i])
+		}
+		if seen[r.Cannon.Generation()] {
+			t.Errorf("Cannon generation %d reserved twice", r.Cannon.Generation())
+		}
+		seen[r.Cannon.Generation()] = true
+	}
+
+	if _, err := manager.Reserve(context.Background()); !errors.Is(err, ErrNoCannonAvailable) {
+		t.Errorf("Expected ErrNoCannonAvailable when every cannon is reserved, got %v", err)
+	}
+}
+
+func TestManager_Reserve_PriorityAndRelease(t *testing.T) {
+	manager := newReservationTestManager()
+
+	first, err := manager.Reserve(context.Background())
+	if err != nil {
+		t.Fatalf("Manager.Reserve() error = %v", err)
+	}
+	if first.Cannon.Generation() != Generation1 {
+		t.Errorf("Expected generation 1 first, got %d", first.Cannon.Generation())
+	}
+
+	second, err := manager.Reserve(context.Background())
+	if err != nil {
+		t.Fatalf("Manager.Reserve() error = %v", err)
+	}
+	if second.Cannon.Generation() != Generation2 {
+		t.Errorf("Expected generation 2 while generation 1 is reserved, got %d", second.Cannon.Generation())
+	}
+
+	manager.Release(first)
+
+	third, err := manager.Reserve(context.Background())
+	if err != nil {
+		t.Fatalf("Manager.Reserve() error = %v", err)
+	}
+	if third.Cannon.Generation() != Generation1 {
+		t.Errorf("Expected released generation 1 to be reserved again, got %d", third.Cannon.Generation())
+	}
+}
+
+func TestManager_Commit(t *testing.T) {
+	req := &FireRequest{Target: target.Position{X: 0, Y: 10}, Enemies: 10}
+
+	t.Run("fires reserved cannon", func(t *testing.T) {
+		manager := newReservationTestManager()
+
+		r, err := manager.Reserve(context.Background())
+		if err != nil {
+			t.Fatalf("Manager.Reserve() error = %v", err)
+		}
+
+		resp, err := manager.Commit(context.Background(), r, req)
+		if err != nil {
+			t.Fatalf("Manager.Commit() error = %v", err)
+		}
+		if resp.Generation != 1 {
+			t.Errorf("Expected generation 1 to fire, got %d", resp.Generation)
+		}
+
+		if manager.isReserved(r.Cannon) {
+			t.Error("Expected reservation to be released after commit")
+		}
+	})
+
+	t.Run("expired lease", func(t *testing.T) {
+		manager := newReservationTestManager(WithLeaseTimeout(time.Millisecond))
+
+		r, err := manager.Reserve(context.Background())
+		if err != nil {
+			t.Fatalf("Manager.Reserve() error = %v", err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		// The expired cannon can be claimed by another request
+		other, err := manager.Reserve(context.Background())
+		if err != nil {
+			t.Fatalf("Manager.Reserve() error = %v", err)
+		}
+		if other.Cannon != r.Cannon {
+			t.Errorf("Expected expired cannon to be reserved again, got generation %d", other.Cannon.Generation())
+		}
+
+		if _, err := manager.Commit(context.Background(), r, req); !errors.Is(err, ErrReservationExpired) {
+			t.Errorf("Expected ErrReservationExpired, got %v", err)
+		}
+	})
+
+	t.Run("direct fire refuses reserved cannon", func(t *testing.T) {
+		manager := newReservationTestManager()
+
+		r, err := manager.Reserve(context.Background())
+		if err != nil {
+			t.Fatalf("Manager.Reserve() error = %v", err)
+		}
+
+		if _, err := manager.Fire(context.Background(), r.Cannon, req); !errors.Is(err, ErrCannonReserved) {
+			t.Errorf("Expected ErrCannonReserved, got %v", err)
+		}
+	})
+}
+
+// gatedHTTPClient reports every cannon available and holds each fire
+// request until release is closed
+type gatedHTTPClient struct {
+	firing  chan struct{}
+	release chan struct{}
+}
+
+func (c *gatedHTTPClient) GetStatus(ctx context.Context, baseURL string) (*Status, error) {
+	return &Status{Available: true}, nil
+}
+
+func (c *gatedHTTPClient) Fire(ctx context.Context, baseURL string, req *FireRequest) (*FireResponse, error) {
+	c.firing <- struct{}{}
+	<-c.release
+	return &FireResponse{Casualties: req.Enemies}, nil
+}
+
+func TestManager_Reserve_WhileCommitting(t *testing.T) {
+	client := &gatedHTTPClient{firing: make(chan struct{}, 1), release: make(chan struct{})}
+	manager := NewManager([]*IonCannon{
+		NewIonCannon(Generation1, "http://cannon1", client, WithFireTimeout(0)),
+		NewIonCannon(Generation2, "http://cannon2", client, WithFireTimeout(0)),
+	})
+
+	first, err := manager.Reserve(context.Background())
+	if err != nil {
+		t.Fatalf("Manager.Reserve() error = %v", err)
+	}
+
+	committed := make(chan error, 1)
+	go func() {
+		_, err := manager.Commit(context.Background(), first, &FireRequest{})
+		committed <- err
+	}()
+	<-client.firing
+	defer func() {
+		close(client.release)
+		if err := <-committed; err != nil {
+			t.Errorf("Manager.Commit() error = %v", err)
+		}
+	}()
+
+	// The cannon being fired must not hold up reserving the free one
+	reserved := make(chan *Reservation, 1)
+	go func() {
+		r, err := manager.Reserve(context.Background())
+		if err != nil {
+			t.Errorf("Manager.Reserve() error = %v", err)
+		}
+		reserved <- r
+	}()
+
+	var second *Reservation
+	select {
+	case second = <-reserved:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Manager.Reserve() blocked while another cannon was firing")
+	}
+	if second != nil && second.Cannon == first.Cannon {
+		t.Errorf("Cannon generation %d reserved twice", first.Cannon.Generation())
+	}
+}
@@ -50,24 +50,90 @@ type FireResponse struct {
 	Generation int `json:"generation"`
 }
 
+// Default per-phase deadlines for cannon calls (OBR-003 BR-3)
+const (
+	DefaultStatusTimeout = 100 * time.Millisecond
+	DefaultFireTimeout   = 500 * time.Millisecond
+)
+
 // IonCannon represents a single ion cannon
 type IonCannon struct {
 	generation  Generation
 	baseURL     string
 	lastFired   time.Time
+	firing      bool
 	mu          sync.RWMutex
 	httpClient  HTTPClient
 	statusCache *StatusCache
+	staleGrace  time.Duration
+	breaker     *CircuitBreaker
+
+	statusTimeout time.Duration
+	fireTimeout   time.Duration
+	observer      Observer
+	blastRadius   float64
+
+	lastErr   error
+	lastErrAt time.Time
+	errMu     sync.Mutex
+}
+
+// CannonOption configures optional IonCannon behaviour
+type CannonOption func(*IonCannon)
+
+// WithCircuitBreaker replaces the default circuit breaker configuration
+func WithCircuitBreaker(config BreakerConfig) CannonOption {
+	return func(c *IonCannon) {
+		c.breaker = NewCircuitBreaker(c.generation, config)
+	}
+}
+
+// WithStaleGrace lets CheckStatus fall back to a cached status up to the given
+// age when the cannon cannot be reached
+func WithStaleGrace(d time.Duration) CannonOption {
+	return func(c *IonCannon) {
+		c.staleGrace = d
+	}
+}
+
+// WithStatusTimeout bounds each status check; zero leaves it to the caller's context
+func WithStatusTimeout(d time.Duration) CannonOption {
+	return func(c *IonCannon) {
+		c.statusTimeout = d
+	}
+}
+
+// WithFireTimeout bounds each fire request; zero leaves it to the caller's context
+func WithFireTimeout(d time.Duration) CannonOption {
+	return func(c *IonCannon) {
+		c.fireTimeout = d
+	}
+}
+
+// WithBlastRadius sets how far in km from the impact point a shot causes damage
+func WithBlastRadius(km float64) CannonOption {
+	return func(c *IonCannon) {
+		c.blastRadius = km
+	}
 }
 
 // NewIonCannon creates a new ion cannon instance
-func NewIonCannon(generation Generation, baseURL string, client HTTPClient) *IonCannon {
-	return &IonCannon{
+func NewIonCannon(generation Generation, baseURL string, client HTTPClient, opts ...CannonOption) *IonCannon {
+	c := &IonCannon{
 		generation:  generation,
 		baseURL:     baseURL,
 		httpClient:  client,
 		statusCache: NewStatusCache(100 * time.Millisecond),
+		breaker:     NewCircuitBreaker(generation, DefaultBreakerConfig()),
+
+		statusTimeout: DefaultStatusTimeout,
+		fireTimeout:   DefaultFireTimeout,
+		observer:      nopObserver{},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // Generation returns the cannon's generation
@@ -75,11 +141,30 @@ func (c *IonCannon) Generation() Generation {
 	return c.generation
 }
 
+// BlastRadius returns how far in km from the impact point a shot causes
+// damage; zero means only the impact point itself
+func (c *IonCannon) BlastRadius() float64 {
+	return c.blastRadius
+}
+
+// BreakerState returns the state of the cannon's circuit breaker
+func (c *IonCannon) BreakerState() BreakerState {
+	return c.breaker.State()
+}
+
 // IsAvailable checks if the cannon is available based on its fire time
 func (c *IonCannon) IsAvailable() bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	return c.isAvailable()
+}
+
+// isAvailable reports availability without locking; callers must hold c.mu
+func (c *IonCannon) isAvailable() bool {
+	if c.firing {
+		return false
+	}
 	if c.lastFired.IsZero() {
 		return true
 	}
@@ -94,9 +179,45 @@ func (c *IonCannon) CheckStatus(ctx context.Context) (*Status, error) {
 		return status, nil
 	}
 
-	// Make HTTP request
-	status, err := c.httpClient.GetStatus(ctx, c.baseURL)
+	// The last refresh failed: serve the last known status within the grace
+	// window instead of waiting on the cannon again on the request path
+	if c.statusCache.Failed() {
+		if stale := c.statusCache.GetStale(c.staleGrace); stale != nil && c.breaker.State() != BreakerOpen {
+			return stale, nil
+		}
+	}
+
+	status, err := c.RefreshStatus(ctx)
+	if err != nil {
+		// Serve stale data within the grace window rather than failing
+		if stale := c.statusCache.GetStale(c.staleGrace); stale != nil && c.breaker.State() != BreakerOpen {
+			return stale, nil
+		}
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// RefreshStatus fetches the cannon's status via HTTP, bypassing the cache
+func (c *IonCannon) RefreshStatus(ctx context.Context) (*Status, error) {
+	// Fail fast while the cannon is known to be failing
+	if !c.breaker.Allow() {
+		c.statusCache.MarkFailed()
+		return nil, fmt.Errorf("cannon generation %d: %w", c.generation, ErrCircuitOpen)
+	}
+
+	// Make HTTP request within the status deadline
+	phaseCtx, cancel := withPhaseTimeout(ctx, c.statusTimeout)
+	defer cancel()
+
+	status, err := c.httpClient.GetStatus(phaseCtx, c.baseURL)
+	err = c.phaseError(ctx, phaseCtx, PhaseStatus, err)
+	c.recordResult(ctx, err)
 	if err != nil {
+		if ctx.Err() == nil {
+			c.statusCache.MarkFailed()
+		}
 		return nil, fmt.Errorf("failed to get cannon status: %w", err)
 	}
 
@@ -105,25 +226,121 @@ func (c *IonCannon) CheckStatus(ctx context.Context) (*Status, error) {
 	return status, nil
 }
 
-// Fire sends a fire request to the cannon
+// Fire sends a fire request to the cannon. The cannon counts as unavailable
+// while the request is in flight, but its lock is not held across the call.
 func (c *IonCannon) Fire(ctx context.Context, req *FireRequest) (*FireResponse, error) {
+	if err := c.startFiring(); err != nil {
+		return nil, err
+	}
+
+	// Send fire request within the fire deadline
+	phaseCtx, cancel := withPhaseTimeout(ctx, c.fireTimeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := c.httpClient.Fire(phaseCtx, c.baseURL, req)
+	c.observer.CannonFired(c.generation, time.Since(start), err)
+	err = c.phaseError(ctx, phaseCtx, PhaseFire, err)
+	c.recordResult(ctx, err)
+	c.stopFiring(err == nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fire cannon: %w", err)
+	}
+
+	return resp, nil
+}
+
+// startFiring claims the cannon for a fire request
+func (c *IonCannon) startFiring() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Double check availability
-	if !c.IsAvailable() {
-		return nil, fmt.Errorf("cannon generation %d is not available", c.generation)
+	if !c.isAvailable() {
+		return fmt.Errorf("cannon generation %d: %w", c.generation, ErrCannonNotReady)
 	}
 
-	// Send fire request
-	resp, err := c.httpClient.Fire(ctx, c.baseURL, req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fire cannon: %w", err)
+	// Fail fast while the cannon is known to be failing
+	if !c.breaker.Allow() {
+		return fmt.Errorf("cannon generation %d: %w", c.generation, ErrCircuitOpen)
 	}
 
-	// Update last fired time
-	c.lastFired = time.Now()
-	return resp, nil
+	c.firing = true
+	return nil
+}
+
+// stopFiring ends a fire request, starting the recharge when the cannon fired
+func (c *IonCannon) stopFiring(fired bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.firing = false
+	if fired {
+		c.lastFired = time.Now()
+	}
+}
+
+// withPhaseTimeout derives the context for a single cannon call
+func withPhaseTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// phaseError wraps err in a PhaseError when the call ran out of its own
+// deadline rather than the caller's
+func (c *IonCannon) phaseError(ctx, phaseCtx context.Context, phase Phase, err error) error {
+	if err == nil || ctx.Err() != nil || phaseCtx.Err() != context.DeadlineExceeded {
+		return err
+	}
+
+	c.observer.CannonTimedOut(c.generation, phase)
+	return &PhaseError{Generation: c.generation, Phase: phase, Err: err}
+}
+
+// recordResult feeds the outcome of a cannon call into the circuit breaker.
+// Calls aborted by the caller say nothing about the cannon and only settle
+// the call.
+func (c *IonCannon) recordResult(ctx context.Context, err error) {
+	switch {
+	case err == nil:
+		c.breaker.RecordSuccess()
+	case ctx.Err() != nil:
+		c.breaker.RecordAborted()
+	default:
+		c.breaker.RecordFailure()
+
+		c.errMu.Lock()
+		c.lastErr = err
+		c.lastErrAt = time.Now()
+		c.errMu.Unlock()
+	}
+}
+
+// LastError returns when the most recent cannon call failed and its error,
+// or a nil error if no call has failed yet
+func (c *IonCannon) LastError() (time.Time, error) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+
+	return c.lastErrAt, c.lastErr
+}
+
+// ReadyIn returns how long until the cannon has recharged after its last shot
+func (c *IonCannon) ReadyIn() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.lastFired.IsZero() {
+		return 0
+	}
+
+	remaining := time.Duration(c.generation.FireTime()*float64(time.Second)) - time.Since(c.lastFired)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
 }
 
 // HTTPClient defines the interface for making HTTP requests to ion cannons
@@ -137,6 +354,7 @@ type StatusCache struct {
 	status    *Status
 	timestamp time.Time
 	ttl       time.Duration
+	failed    bool
 	mu        sync.RWMutex
 }
 
@@ -158,6 +376,18 @@ func (c *StatusCache) Get() *Status {
 	return c.status
 }
 
+// GetStale returns the cached status if it is no older than maxAge, even when
+// it is past the TTL
+func (c *StatusCache) GetStale(maxAge time.Duration) *Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.status == nil || time.Since(c.timestamp) > maxAge {
+		return nil
+	}
+	return c.status
+}
+
 // Set updates the cached status and timestamp
 func (c *StatusCache) Set(status *Status) {
 	c.mu.Lock()
@@ -165,4 +395,21 @@ func (c *StatusCache) Set(status *Status) {
 
 	c.status = status
 	c.timestamp = time.Now()
+	c.failed = false
+}
+
+// MarkFailed records that the latest refresh failed
+func (c *StatusCache) MarkFailed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failed = true
+}
+
+// Failed reports whether the latest refresh failed
+func (c *StatusCache) Failed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.failed
 }
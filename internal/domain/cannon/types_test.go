@@ -0,0 +1,74 @@
+package cannon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIonCannon_Fire(t *testing.T) {
+	client := &MockHTTPClient{
+		fireResponses: map[string]*FireResponse{
+			"http://cannon1": {Casualties: 10, Generation: 1},
+		},
+	}
+	c := NewIonCannon(Generation1, "http://cannon1", client)
+
+	// Fire checks availability while holding its own lock, so it must not
+	// take the lock again through IsAvailable
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Fire(context.Background(), &FireRequest{})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Fire() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Fire() deadlocked")
+	}
+
+	if c.IsAvailable() {
+		t.Error("IsAvailable() = true right after firing, want false")
+	}
+	if _, err := c.Fire(context.Background(), &FireRequest{}); err == nil {
+		t.Error("Fire() while recharging succeeded, want an error")
+	}
+}
+
+func TestIonCannon_IsAvailable_WhileFiring(t *testing.T) {
+	client := &gatedHTTPClient{firing: make(chan struct{}, 1), release: make(chan struct{})}
+	c := NewIonCannon(Generation1, "http://cannon1", client, WithFireTimeout(0))
+
+	fired := make(chan error, 1)
+	go func() {
+		_, err := c.Fire(context.Background(), &FireRequest{})
+		fired <- err
+	}()
+	<-client.firing
+
+	// The fire request is in flight: the cannon is busy, but asking must not block
+	available := make(chan bool, 1)
+	go func() { available <- c.IsAvailable() }()
+	select {
+	case ok := <-available:
+		if ok {
+			t.Error("IsAvailable() = true while firing, want false")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("IsAvailable() blocked while the cannon was firing")
+	}
+
+	if _, err := c.Fire(context.Background(), &FireRequest{}); !errors.Is(err, ErrCannonNotReady) {
+		t.Errorf("Fire() while firing error = %v, want ErrCannonNotReady", err)
+	}
+
+	close(client.release)
+	if err := <-fired; err != nil {
+		t.Errorf("Fire() error = %v", err)
+	}
+}
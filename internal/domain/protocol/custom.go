@@ -0,0 +1,106 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// ExpressionKind selects how an expression protocol uses its expression
+type ExpressionKind string
+
+const (
+	// ExpressionFilter keeps only the targets matching the expression,
+	// like avoid-mech
+	ExpressionFilter ExpressionKind = "filter"
+	// ExpressionPrefer keeps the matching targets when there are any and
+	// all targets otherwise, like prioritize-mech
+	ExpressionPrefer ExpressionKind = "prefer"
+)
+
+// ExpressionProtocol is a protocol defined by an expression rather than Go code
+type ExpressionProtocol struct {
+	name string
+	kind ExpressionKind
+	expr *Expression
+}
+
+// NewExpressionProtocol creates a protocol that filters or prefers the
+// targets matching expr
+func NewExpressionProtocol(name string, kind ExpressionKind, expr *Expression) *ExpressionProtocol {
+	return &ExpressionProtocol{
+		name: name,
+		kind: kind,
+		expr: expr,
+	}
+}
+
+func (p *ExpressionProtocol) Name() string {
+	return p.name
+}
+
+func (p *ExpressionProtocol) Apply(targets []*target.Target) ([]*target.Target, error) {
+	result := make([]*target.Target, 0, len(targets))
+	for _, t := range targets {
+		if p.expr.Match(t) {
+			result = append(result, t)
+		}
+	}
+
+	// A preference never leaves the chain empty
+	if p.kind == ExpressionPrefer && len(result) == 0 {
+		return targets, nil
+	}
+	return result, nil
+}
+
+// AvoidsCollateral implements CollateralAware for expressions that read endangers_allies
+func (p *ExpressionProtocol) AvoidsCollateral() bool {
+	return p.expr.collateral
+}
+
+// Score implements Scorer by favouring targets that match the expression
+// and, for filters, penalizing those that do not
+func (p *ExpressionProtocol) Score(targets []*target.Target) []float64 {
+	return scoreEach(targets, func(t *target.Target) float64 {
+		switch {
+		case p.expr.Match(t):
+			return 1
+		case p.kind == ExpressionFilter:
+			return -1
+		default:
+			return 0
+		}
+	})
+}
+
+// ExpressionDefinition declares an expression protocol, typically loaded
+// from configuration
+type ExpressionDefinition struct {
+	Name      string
+	Kind      ExpressionKind
+	Tier      Tier
+	Expr      string
+	Conflicts []string
+}
+
+// Definition compiles the expression and returns the registry definition
+func (d ExpressionDefinition) Definition() (Definition, error) {
+	switch d.Kind {
+	case ExpressionFilter, ExpressionPrefer:
+	default:
+		return Definition{}, fmt.Errorf("%w: %s has unknown kind %q", ErrInvalidDefinition, d.Name, d.Kind)
+	}
+
+	expr, err := CompileExpression(d.Expr)
+	if err != nil {
+		return Definition{}, fmt.Errorf("protocol %s: %w", d.Name, err)
+	}
+
+	return Definition{
+		Name:      d.Name,
+		Tier:      d.Tier,
+		Conflicts: d.Conflicts,
+		New:       func() Protocol { return NewExpressionProtocol(d.Name, d.Kind, expr) },
+	}, nil
+}
@@ -0,0 +1,99 @@
+package protocol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+func TestExpressionProtocol(t *testing.T) {
+	targets := createTestTargets()
+
+	tests := []struct {
+		name string
+		kind ExpressionKind
+		expr string
+		want int
+	}{
+		{name: "filter keeps matches", kind: ExpressionFilter, expr: "enemies.number >= 5 && !has_allies", want: 1},
+		{name: "filter may remove everything", kind: ExpressionFilter, expr: "distance > 90", want: 0},
+		{name: "prefer keeps matches", kind: ExpressionPrefer, expr: "distance < 25", want: 2},
+		{name: "prefer without matches keeps all", kind: ExpressionPrefer, expr: "distance > 90", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := CompileExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			result, err := NewExpressionProtocol("custom", tt.kind, expr).Apply(targets)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result) != tt.want {
+				t.Errorf("Expected %d targets, got %d", tt.want, len(result))
+			}
+		})
+	}
+}
+
+func TestExpressionDefinition(t *testing.T) {
+	r := newBuiltinRegistry()
+
+	def, err := ExpressionDefinition{
+		Name:      "big-groups",
+		Kind:      ExpressionFilter,
+		Tier:      TierValidation,
+		Expr:      "enemies.number >= 5",
+		Conflicts: []string{"least-enemies"},
+	}.Definition()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Register(def); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The declared tier runs the custom filter before closest-enemies
+	chain, err := r.CreateChain([]string{"closest-enemies", "big-groups"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err := ApplyProtocolChain(chain, createTestTargets())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 1 || result[0].Coordinates != (target.Position{X: 0, Y: 20}) {
+		t.Errorf("Expected the closest big group at (0,20), got %+v", result)
+	}
+
+	if err := r.Validate([]string{"least-enemies", "big-groups"}); !errors.Is(err, ErrIncompatibleProtocols) {
+		t.Errorf("Expected declared conflict to be enforced, got %v", err)
+	}
+
+	invalid := []ExpressionDefinition{
+		{Name: "no-kind", Tier: TierTactical, Expr: "distance < 10"},
+		{Name: "bad-expr", Kind: ExpressionPrefer, Tier: TierTactical, Expr: "distance <"},
+	}
+	for _, d := range invalid {
+		if _, err := d.Definition(); err == nil {
+			t.Errorf("Expected error for definition %s", d.Name)
+		}
+	}
+}
+
+func TestParseTier(t *testing.T) {
+	for tier := TierValidation; tier <= TierTactical; tier++ {
+		got, err := ParseTier(tier.String())
+		if err != nil || got != tier {
+			t.Errorf("ParseTier(%q) = %v, %v", tier.String(), got, err)
+		}
+	}
+
+	if _, err := ParseTier("strategic"); !errors.Is(err, ErrInvalidDefinition) {
+		t.Errorf("Expected ErrInvalidDefinition, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package protocol
+
+import "errors"
+
+var (
+	// ErrInvalidProtocol is returned for a protocol name that is not registered
+	ErrInvalidProtocol = errors.New("invalid protocol")
+	// ErrIncompatibleProtocols is returned when two requested protocols conflict
+	ErrIncompatibleProtocols = errors.New("incompatible protocols")
+	// ErrInvalidDefinition is returned when registering an incomplete protocol definition
+	ErrInvalidDefinition = errors.New("invalid protocol definition")
+	// ErrDuplicateProtocol is returned when registering a name twice
+	ErrDuplicateProtocol = errors.New("protocol already registered")
+	// ErrNotScorable is returned when a protocol without a Scorer is used in scoring mode
+	ErrNotScorable = errors.New("protocol does not support scoring")
+	// ErrInvalidExpression is returned when a protocol expression does not compile
+	ErrInvalidExpression = errors.New("invalid protocol expression")
+	// ErrInvalidTieBreaker is returned for an unknown tie-breaker policy
+	ErrInvalidTieBreaker = errors.New("invalid tie breaker")
+)
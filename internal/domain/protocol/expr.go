@@ -0,0 +1,414 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// Expression is a compiled boolean expression over target fields, such as
+// `enemies.number >= 5 && !has_allies`. It supports number and boolean
+// literals, the fields below, arithmetic (+ - * /), comparisons
+// (== != < <= > >=), logic (&& || !) and parentheses. Expressions are type
+// checked when compiled and cannot loop or call out, so they are safe to load
+// from configuration.
+type Expression struct {
+	src        string
+	match      func(*target.Target) bool
+	collateral bool
+}
+
+// numberFields are the numeric target fields available to expressions
+var numberFields = map[string]func(*target.Target) float64{
+	"distance":         func(t *target.Target) float64 { return t.Distance() },
+	"angle":            func(t *target.Target) float64 { return t.Angle() },
+	"max_range":        func(t *target.Target) float64 { return t.MaxRange() },
+	"allies":           func(t *target.Target) float64 { return float64(allies(t)) },
+	"enemies.number":   func(t *target.Target) float64 { return float64(t.EnemyCount()) },
+	"enemies.soldiers": func(t *target.Target) float64 { return float64(t.Enemies.Count(target.EnemyTypeSoldier)) },
+	"enemies.mechs":    func(t *target.Target) float64 { return float64(t.Enemies.Count(target.EnemyTypeMech)) },
+}
+
+// boolFields are the boolean target fields available to expressions
+var boolFields = map[string]func(*target.Target) bool{
+	"has_allies":       func(t *target.Target) bool { return t.HasAllies() },
+	"endangers_allies": func(t *target.Target) bool { return t.EndangersAllies() },
+	"is_mech":          func(t *target.Target) bool { return t.IsMech() },
+}
+
+// ExpressionFields returns the sorted names of the fields expressions may use
+func ExpressionFields() []string {
+	names := make([]string, 0, len(numberFields)+len(boolFields))
+	for name := range numberFields {
+		names = append(names, name)
+	}
+	for name := range boolFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CompileExpression parses and type checks a boolean expression
+func CompileExpression(src string) (*Expression, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	v, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	if v.truth == nil {
+		return nil, fmt.Errorf("%w: expression must be true or false, not a number", ErrInvalidExpression)
+	}
+
+	return &Expression{src: src, match: v.truth, collateral: p.collateral}, nil
+}
+
+// Match reports whether the target satisfies the expression
+func (e *Expression) Match(t *target.Target) bool {
+	return e.match(t)
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.src
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators lists every operator, two-character ones first
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "(", ")"}
+
+// lex splits an expression into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, src[start:i], start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_' || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, src[start:i], start})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: unexpected character %q at position %d", ErrInvalidExpression, c, i+1)
+			}
+			tokens = append(tokens, token{tokenOp, op, i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "end of expression", len(src)}), nil
+}
+
+// value is a compiled sub-expression; exactly one of num and truth is set
+type value struct {
+	num   func(*target.Target) float64
+	truth func(*target.Target) bool
+}
+
+// parser is a recursive descent parser producing closures. From lowest to
+// highest precedence: ||, &&, comparisons, + -, * /, unary ! -.
+type parser struct {
+	tokens []token
+	pos    int
+
+	// collateral is set once the expression reads endangers_allies
+	collateral bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the given operators
+func (p *parser) accept(ops ...string) (token, bool) {
+	tok := p.peek()
+	if tok.kind == tokenOp {
+		for _, op := range ops {
+			if tok.text == op {
+				p.pos++
+				return tok, true
+			}
+		}
+	}
+	return tok, false
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidExpression, fmt.Sprintf(format, args...), tok.pos+1)
+}
+
+func (p *parser) parseOr() (value, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return value{}, err
+	}
+
+	for {
+		tok, ok := p.accept("||")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return value{}, err
+		}
+		if left.truth == nil || right.truth == nil {
+			return value{}, p.errorf(tok, "|| needs true or false on both sides")
+		}
+		l, r := left.truth, right.truth
+		left = value{truth: func(t *target.Target) bool { return l(t) || r(t) }}
+	}
+}
+
+func (p *parser) parseAnd() (value, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return value{}, err
+	}
+
+	for {
+		tok, ok := p.accept("&&")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return value{}, err
+		}
+		if left.truth == nil || right.truth == nil {
+			return value{}, p.errorf(tok, "&& needs true or false on both sides")
+		}
+		l, r := left.truth, right.truth
+		left = value{truth: func(t *target.Target) bool { return l(t) && r(t) }}
+	}
+}
+
+func (p *parser) parseComparison() (value, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return value{}, err
+	}
+
+	tok, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return value{}, err
+	}
+
+	// Booleans only compare for equality
+	if left.truth != nil && right.truth != nil {
+		l, r := left.truth, right.truth
+		switch tok.text {
+		case "==":
+			return value{truth: func(t *target.Target) bool { return l(t) == r(t) }}, nil
+		case "!=":
+			return value{truth: func(t *target.Target) bool { return l(t) != r(t) }}, nil
+		}
+	}
+	if left.num == nil || right.num == nil {
+		return value{}, p.errorf(tok, "%s needs numbers on both sides", tok.text)
+	}
+
+	l, r := left.num, right.num
+	var cmp func(a, b float64) bool
+	switch tok.text {
+	case "==":
+		cmp = func(a, b float64) bool { return a == b }
+	case "!=":
+		cmp = func(a, b float64) bool { return a != b }
+	case "<":
+		cmp = func(a, b float64) bool { return a < b }
+	case "<=":
+		cmp = func(a, b float64) bool { return a <= b }
+	case ">":
+		cmp = func(a, b float64) bool { return a > b }
+	case ">=":
+		cmp = func(a, b float64) bool { return a >= b }
+	}
+	return value{truth: func(t *target.Target) bool { return cmp(l(t), r(t)) }}, nil
+}
+
+func (p *parser) parseAdditive() (value, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return value{}, err
+	}
+
+	for {
+		tok, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return value{}, err
+		}
+		if left, err = p.arithmetic(tok, left, right); err != nil {
+			return value{}, err
+		}
+	}
+}
+
+func (p *parser) parseMultiplicative() (value, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return value{}, err
+	}
+
+	for {
+		tok, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return value{}, err
+		}
+		if left, err = p.arithmetic(tok, left, right); err != nil {
+			return value{}, err
+		}
+	}
+}
+
+// arithmetic combines two numbers with the operator in tok
+func (p *parser) arithmetic(tok token, left, right value) (value, error) {
+	if left.num == nil || right.num == nil {
+		return value{}, p.errorf(tok, "%s needs numbers on both sides", tok.text)
+	}
+
+	l, r := left.num, right.num
+	switch tok.text {
+	case "+":
+		return value{num: func(t *target.Target) float64 { return l(t) + r(t) }}, nil
+	case "-":
+		return value{num: func(t *target.Target) float64 { return l(t) - r(t) }}, nil
+	case "*":
+		return value{num: func(t *target.Target) float64 { return l(t) * r(t) }}, nil
+	default:
+		return value{num: func(t *target.Target) float64 { return l(t) / r(t) }}, nil
+	}
+}
+
+func (p *parser) parseUnary() (value, error) {
+	tok, ok := p.accept("!", "-")
+	if !ok {
+		return p.parsePrimary()
+	}
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return value{}, err
+	}
+
+	if tok.text == "!" {
+		if operand.truth == nil {
+			return value{}, p.errorf(tok, "! needs true or false")
+		}
+		f := operand.truth
+		return value{truth: func(t *target.Target) bool { return !f(t) }}, nil
+	}
+
+	if operand.num == nil {
+		return value{}, p.errorf(tok, "- needs a number")
+	}
+	f := operand.num
+	return value{num: func(t *target.Target) float64 { return -f(t) }}, nil
+}
+
+func (p *parser) parsePrimary() (value, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return value{}, p.errorf(tok, "invalid number %q", tok.text)
+		}
+		return value{num: func(*target.Target) float64 { return n }}, nil
+
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return value{truth: func(*target.Target) bool { return true }}, nil
+		case "false":
+			return value{truth: func(*target.Target) bool { return false }}, nil
+		}
+		if f, ok := numberFields[tok.text]; ok {
+			return value{num: f}, nil
+		}
+		if f, ok := boolFields[tok.text]; ok {
+			p.collateral = p.collateral || tok.text == "endangers_allies"
+			return value{truth: f}, nil
+		}
+		return value{}, p.errorf(tok, "unknown field %q", tok.text)
+
+	case tokenOp:
+		if tok.text == "(" {
+			v, err := p.parseOr()
+			if err != nil {
+				return value{}, err
+			}
+			if closing, ok := p.accept(")"); !ok {
+				return value{}, p.errorf(closing, "expected )")
+			}
+			return v, nil
+		}
+	}
+
+	return value{}, p.errorf(tok, "unexpected %q", tok.text)
+}
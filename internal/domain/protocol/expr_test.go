@@ -0,0 +1,74 @@
+package protocol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+func TestCompileExpression(t *testing.T) {
+	allies := 3
+	// 50 km away with 8 soldiers, 2 mechs and allies
+	tgt := target.NewTarget(
+		target.Position{X: 30, Y: 40},
+		target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 8}, {Type: target.EnemyTypeMech, Number: 2}},
+		&allies,
+	)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"enemies.number >= 5 && !has_allies", false},
+		{"enemies.number >= 5 && has_allies", true},
+		{"distance < 40", false},
+		{"distance <= 50", true},
+		{"is_mech && enemies.mechs == 2", true},
+		{"enemies.soldiers - enemies.mechs * 2 == 4", true},
+		{"(enemies.soldiers - enemies.mechs) * 2 == 12", true},
+		{"allies / enemies.number > 0.25", true},
+		{"distance / max_range >= 0.5", true},
+		{"-distance < -49", true},
+		{"false || allies != 3", false},
+		{"has_allies == is_mech", true},
+		{"angle > 50 && angle < 54", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := CompileExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := expr.Match(tgt); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileExpression_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"distance",
+		"distance < ",
+		"armor > 3",
+		"has_allies + 1 > 2",
+		"distance && has_allies",
+		"!distance",
+		"has_allies < is_mech",
+		"(distance < 40",
+		"distance < 40)",
+		"distance < 40 # comment",
+		"1.2.3 > distance",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			if _, err := CompileExpression(src); !errors.Is(err, ErrInvalidExpression) {
+				t.Errorf("Expected ErrInvalidExpression, got %v", err)
+			}
+		})
+	}
+}
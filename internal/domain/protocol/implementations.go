@@ -27,7 +27,8 @@ func (p *AvoidMechProtocol) Apply(targets []*target.Target) ([]*target.Target, e
 	return result, nil
 }
 
-// AvoidCrossfireProtocol filters out targets with allies
+// AvoidCrossfireProtocol filters out targets whose strike would hit allies,
+// at the target or within the blast radius of the firing cannon
 type AvoidCrossfireProtocol struct{}
 
 func NewAvoidCrossfireProtocol() *AvoidCrossfireProtocol {
@@ -41,13 +42,18 @@ func (p *AvoidCrossfireProtocol) Name() string {
 func (p *AvoidCrossfireProtocol) Apply(targets []*target.Target) ([]*target.Target, error) {
 	result := make([]*target.Target, 0, len(targets))
 	for _, t := range targets {
-		if !t.HasAllies() {
+		if !t.EndangersAllies() {
 			result = append(result, t)
 		}
 	}
 	return result, nil
 }
 
+// AvoidsCollateral implements CollateralAware
+func (p *AvoidCrossfireProtocol) AvoidsCollateral() bool {
+	return true
+}
+
 // PrioritizeMechProtocol prioritizes mech targets
 type PrioritizeMechProtocol struct{}
 
@@ -100,10 +106,10 @@ func (p *ClosestEnemiesProtocol) Apply(targets []*target.Target) ([]*target.Targ
 		return targets, nil
 	}
 
-	// Sort by distance
+	// Sort by distance, keeping equidistant targets in scan order
 	sorted := make([]*target.Target, len(targets))
 	copy(sorted, targets)
-	sort.Slice(sorted, func(i, j int) bool {
+	sort.SliceStable(sorted, func(i, j int) bool {
 		return sorted[i].Distance() < sorted[j].Distance()
 	})
 
@@ -136,10 +142,10 @@ func (p *FurthestEnemiesProtocol) Apply(targets []*target.Target) ([]*target.Tar
 		return targets, nil
 	}
 
-	// Sort by distance in descending order
+	// Sort by distance in descending order, keeping equidistant targets in scan order
 	sorted := make([]*target.Target, len(targets))
 	copy(sorted, targets)
-	sort.Slice(sorted, func(i, j int) bool {
+	sort.SliceStable(sorted, func(i, j int) bool {
 		return sorted[i].Distance() > sorted[j].Distance()
 	})
 
@@ -191,3 +197,65 @@ func (p *AssistAlliesProtocol) Apply(targets []*target.Target) ([]*target.Target
 	// If no targets with allies, return all targets unchanged
 	return targets, nil
 }
+
+// MostEnemiesProtocol selects the targets with the most enemies
+type MostEnemiesProtocol struct{}
+
+func NewMostEnemiesProtocol() *MostEnemiesProtocol {
+	return &MostEnemiesProtocol{}
+}
+
+func (p *MostEnemiesProtocol) Name() string {
+	return "most-enemies"
+}
+
+func (p *MostEnemiesProtocol) Apply(targets []*target.Target) ([]*target.Target, error) {
+	if len(targets) <= 1 {
+		return targets, nil
+	}
+
+	maxEnemies := targets[0].EnemyCount()
+	for _, t := range targets[1:] {
+		maxEnemies = max(maxEnemies, t.EnemyCount())
+	}
+
+	// Return only the targets with the maximum number of enemies, in scan order
+	result := make([]*target.Target, 0)
+	for _, t := range targets {
+		if t.EnemyCount() == maxEnemies {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// LeastEnemiesProtocol selects the targets with the fewest enemies
+type LeastEnemiesProtocol struct{}
+
+func NewLeastEnemiesProtocol() *LeastEnemiesProtocol {
+	return &LeastEnemiesProtocol{}
+}
+
+func (p *LeastEnemiesProtocol) Name() string {
+	return "least-enemies"
+}
+
+func (p *LeastEnemiesProtocol) Apply(targets []*target.Target) ([]*target.Target, error) {
+	if len(targets) <= 1 {
+		return targets, nil
+	}
+
+	minEnemies := targets[0].EnemyCount()
+	for _, t := range targets[1:] {
+		minEnemies = min(minEnemies, t.EnemyCount())
+	}
+
+	// Return only the targets with the minimum number of enemies, in scan order
+	result := make([]*target.Target, 0)
+	for _, t := range targets {
+		if t.EnemyCount() == minEnemies {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// Observer receives protocol chain events for instrumentation.
+// Implementations must be safe for concurrent use and must not block.
+type Observer interface {
+	// TargetsSelected is called once per selection with the protocol
+	// combination applied and the targets it chose from
+	TargetsSelected(protocols string, targets []*target.Target)
+	// ProtocolApplied is called after each protocol in a chain runs with the
+	// targets it received and the ones that survived it
+	ProtocolApplied(name string, duration time.Duration, input, output []*target.Target)
+}
+
+// nopObserver discards every event
+type nopObserver struct{}
+
+func (nopObserver) TargetsSelected(string, []*target.Target)                                  {}
+func (nopObserver) ProtocolApplied(string, time.Duration, []*target.Target, []*target.Target) {}
+
+// ChainOption configures how a protocol chain is applied
+type ChainOption func(*chainConfig)
+
+// chainConfig holds the options of a single chain application
+type chainConfig struct {
+	observer Observer
+}
+
+// WithObserver reports the selection and every protocol applied to o
+func WithObserver(o Observer) ChainOption {
+	return func(c *chainConfig) {
+		if o != nil {
+			c.observer = o
+		}
+	}
+}
+
+// newChainConfig applies opts over the defaults
+func newChainConfig(opts []ChainOption) chainConfig {
+	c := chainConfig{observer: nopObserver{}}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// chainName returns the protocol combination of the chain
+func chainName(chain []Protocol) string {
+	names := make([]string, 0, len(chain))
+	for _, p := range chain {
+		names = append(names, p.Name())
+	}
+	return Combination(names)
+}
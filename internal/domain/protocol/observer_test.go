@@ -0,0 +1,88 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// recordingObserver records each selection and the name and surviving count
+// of each protocol applied
+type recordingObserver struct {
+	selections []string
+	applied    []string
+	outputs    []int
+}
+
+func (o *recordingObserver) TargetsSelected(protocols string, targets []*target.Target) {
+	o.selections = append(o.selections, protocols)
+}
+
+func (o *recordingObserver) ProtocolApplied(name string, duration time.Duration, input, output []*target.Target) {
+	o.applied = append(o.applied, name)
+	o.outputs = append(o.outputs, len(output))
+}
+
+func TestWithObserver(t *testing.T) {
+	chain, err := CreateProtocolChain([]string{"closest-enemies", "avoid-mech"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		apply       func(obs Observer) error
+		wantApplied []string
+		wantOutputs []int
+	}{
+		{
+			name: "chain",
+			apply: func(obs Observer) error {
+				_, err := ApplyProtocolChain(chain, createTestTargets(), WithObserver(obs))
+				return err
+			},
+			wantApplied: []string{"avoid-mech", "closest-enemies"},
+			wantOutputs: []int{2, 1},
+		},
+		{
+			name: "ranking",
+			apply: func(obs Observer) error {
+				_, err := RankTargets(chain, createTestTargets(), DefaultTieBreaker, WithObserver(obs))
+				return err
+			},
+			wantApplied: []string{"avoid-mech", "closest-enemies", "avoid-mech", "closest-enemies", "avoid-mech"},
+			wantOutputs: []int{2, 1, 1, 1, 0},
+		},
+		{
+			name: "scoring",
+			apply: func(obs Observer) error {
+				_, err := ScoreTargets(chain, nil, createTestTargets(), WithObserver(obs))
+				return err
+			},
+			wantApplied: []string{"avoid-mech", "closest-enemies"},
+			wantOutputs: []int{2, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer := &recordingObserver{}
+			if err := tt.apply(observer); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Targets are reported once per selection, however often the chain runs
+			if want := []string{"avoid-mech+closest-enemies"}; !reflect.DeepEqual(observer.selections, want) {
+				t.Errorf("Observed selections %v, want %v", observer.selections, want)
+			}
+			if !reflect.DeepEqual(observer.applied, tt.wantApplied) {
+				t.Errorf("Observed protocols %v, want %v", observer.applied, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(observer.outputs, tt.wantOutputs) {
+				t.Errorf("Observed outputs %v, want %v", observer.outputs, tt.wantOutputs)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package protocol
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
 )
@@ -12,100 +15,112 @@ type Protocol interface {
 	Name() string
 }
 
-// ValidateProtocols checks if the provided protocols are valid and compatible
-func ValidateProtocols(protocols []string) error {
-	hasClosest := false
-	hasFurthest := false
-
-	for _, p := range protocols {
-		switch p {
-		case "closest-enemies":
-			if hasFurthest {
-				return fmt.Errorf("incompatible protocols: closest-enemies and furthest-enemies")
-			}
-			hasClosest = true
-		case "furthest-enemies":
-			if hasClosest {
-				return fmt.Errorf("incompatible protocols: closest-enemies and furthest-enemies")
-			}
-			hasFurthest = true
-		case "assist-allies", "avoid-crossfire", "prioritize-mech", "avoid-mech":
-			// These protocols are always compatible
-			continue
-		default:
-			return fmt.Errorf("invalid protocol: %s", p)
+// CollateralAware is implemented by protocols whose choice depends on the
+// blast radius of the cannon that fires, so the target they pick may not be
+// safe for a cannon with a larger radius
+type CollateralAware interface {
+	AvoidsCollateral() bool
+}
+
+// AvoidsCollateral reports whether any protocol in the chain avoids collateral damage
+func AvoidsCollateral(chain []Protocol) bool {
+	for _, p := range chain {
+		if ca, ok := p.(CollateralAware); ok && ca.AvoidsCollateral() {
+			return true
 		}
 	}
-	return nil
+	return false
+}
+
+// ValidateProtocols checks if the provided protocols are valid and compatible
+func ValidateProtocols(protocols []string) error {
+	return defaultRegistry.Validate(protocols)
 }
 
 // CreateProtocolChain creates a chain of protocols in the correct order
 func CreateProtocolChain(protocols []string) ([]Protocol, error) {
-	if err := ValidateProtocols(protocols); err != nil {
-		return nil, err
-	}
+	return defaultRegistry.CreateChain(protocols)
+}
 
-	// Initialize protocol slices by priority
-	var (
-		validationProtocols []Protocol
-		typeProtocols       []Protocol
-		positionProtocols   []Protocol
-		tacticalProtocols   []Protocol
-	)
-
-	// Create protocol instances in the correct order
-	for _, p := range protocols {
-		switch p {
-		// Validation protocols (first)
-		case "avoid-mech":
-			validationProtocols = append(validationProtocols, NewAvoidMechProtocol())
-		case "avoid-crossfire":
-			validationProtocols = append(validationProtocols, NewAvoidCrossfireProtocol())
-
-		// Type protocols (second)
-		case "prioritize-mech":
-			typeProtocols = append(typeProtocols, NewPrioritizeMechProtocol())
-
-		// Position protocols (third)
-		case "closest-enemies":
-			positionProtocols = append(positionProtocols, NewClosestEnemiesProtocol())
-		case "furthest-enemies":
-			positionProtocols = append(positionProtocols, NewFurthestEnemiesProtocol())
-
-		// Tactical protocols (fourth)
-		case "assist-allies":
-			tacticalProtocols = append(tacticalProtocols, NewAssistAlliesProtocol())
-		}
-	}
+// Combination returns the stable name of a set of protocols in the default registry
+func Combination(protocols []string) string {
+	return defaultRegistry.Combination(protocols)
+}
 
-	// Combine all protocols in order
-	result := make([]Protocol, 0,
-		len(validationProtocols)+
-			len(typeProtocols)+
-			len(positionProtocols)+
-			len(tacticalProtocols))
+// Step records the targets a protocol received and the ones that survived it
+type Step struct {
+	Protocol string
+	Input    []*target.Target
+	Output   []*target.Target
+}
 
-	result = append(result, validationProtocols...)
-	result = append(result, typeProtocols...)
-	result = append(result, positionProtocols...)
-	result = append(result, tacticalProtocols...)
+// ApplyProtocolChain applies all protocols in sequence
+func ApplyProtocolChain(chain []Protocol, targets []*target.Target, opts ...ChainOption) ([]*target.Target, error) {
+	obs := newChainConfig(opts).observer
+	obs.TargetsSelected(chainName(chain), targets)
+	return applyChain(chain, targets, obs, nil)
+}
 
-	return result, nil
+// ApplyProtocolChainTrace applies all protocols in sequence and records each step.
+// Steps recorded before a failing protocol are returned alongside the error.
+func ApplyProtocolChainTrace(chain []Protocol, targets []*target.Target, opts ...ChainOption) ([]*target.Target, []Step, error) {
+	obs := newChainConfig(opts).observer
+	obs.TargetsSelected(chainName(chain), targets)
+
+	steps := make([]Step, 0, len(chain))
+	result, err := applyChain(chain, targets, obs, func(s Step) {
+		steps = append(steps, s)
+	})
+	return result, steps, err
 }
 
-// ApplyProtocolChain applies all protocols in sequence
-func ApplyProtocolChain(chain []Protocol, targets []*target.Target) ([]*target.Target, error) {
-	var err error
+// RankTargets orders targets from best to worst as the chain sees them. The
+// chain is applied repeatedly: the targets that survive a round, ordered by
+// the tie-breaker, rank next and are removed before the following round.
+// Targets the chain never selects, such as those a validation protocol
+// discards, are left out.
+func RankTargets(chain []Protocol, targets []*target.Target, tb TieBreaker, opts ...ChainOption) ([]*target.Target, error) {
+	obs := newChainConfig(opts).observer
+	obs.TargetsSelected(chainName(chain), targets)
+
+	ranked := make([]*target.Target, 0, len(targets))
+	remaining := targets
+	for len(remaining) > 0 {
+		best, err := applyChain(chain, remaining, obs, nil)
+		if errors.Is(err, target.ErrNoValidTargets) && len(ranked) > 0 {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ranked = append(ranked, BreakTies(tb, best)...)
+		remaining = slices.DeleteFunc(slices.Clone(remaining), func(t *target.Target) bool {
+			return slices.Contains(best, t)
+		})
+	}
+	return ranked, nil
+}
+
+// applyChain runs the chain, reporting every protocol applied to obs and
+// every step to record when it is set
+func applyChain(chain []Protocol, targets []*target.Target, obs Observer, record func(Step)) ([]*target.Target, error) {
 	current := targets
 
 	for _, p := range chain {
-		current, err = p.Apply(current)
+		start := time.Now()
+		next, err := p.Apply(current)
+		obs.ProtocolApplied(p.Name(), time.Since(start), current, next)
 		if err != nil {
 			return nil, fmt.Errorf("protocol %s failed: %w", p.Name(), err)
 		}
-		if len(current) == 0 {
-			return nil, fmt.Errorf("no valid targets after applying protocol %s", p.Name())
+		if record != nil {
+			record(Step{Protocol: p.Name(), Input: current, Output: next})
+		}
+		if len(next) == 0 {
+			return nil, fmt.Errorf("%w after applying protocol %s", target.ErrNoValidTargets, p.Name())
 		}
+		current = next
 	}
 
 	return current, nil
@@ -33,6 +33,11 @@ func TestValidateProtocols(t *testing.T) {
 			protocols: []string{"closest-enemies", "furthest-enemies"},
 			wantErr:   true,
 		},
+		{
+			name:      "incompatible enemy count protocols",
+			protocols: []string{"least-enemies", "most-enemies"},
+			wantErr:   true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -45,22 +50,64 @@ func TestValidateProtocols(t *testing.T) {
 	}
 }
 
+func TestCreateProtocolChain_OrderIndependent(t *testing.T) {
+	targets := []*target.Target{
+		target.NewTarget(target.Position{X: 0, Y: 10}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 5}}, nil),
+		target.NewTarget(target.Position{X: 0, Y: 50}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 40}}, nil),
+	}
+
+	tests := []struct {
+		name      string
+		protocols [][]string
+		want      target.Position
+	}{
+		{
+			name:      "most enemies with closest",
+			protocols: [][]string{{"most-enemies", "closest-enemies"}, {"closest-enemies", "most-enemies"}},
+			want:      target.Position{X: 0, Y: 50},
+		},
+		{
+			name:      "least enemies with furthest",
+			protocols: [][]string{{"least-enemies", "furthest-enemies"}, {"furthest-enemies", "least-enemies"}},
+			want:      target.Position{X: 0, Y: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, protocols := range tt.protocols {
+				chain, err := CreateProtocolChain(protocols)
+				if err != nil {
+					t.Fatalf("CreateProtocolChain(%v) error = %v", protocols, err)
+				}
+				result, err := ApplyProtocolChain(chain, targets)
+				if err != nil {
+					t.Fatalf("ApplyProtocolChain(%v) error = %v", protocols, err)
+				}
+				if len(result) != 1 || result[0].Coordinates != tt.want {
+					t.Errorf("Protocols %v selected %+v, want %+v", protocols, result, tt.want)
+				}
+			}
+		})
+	}
+}
+
 func createTestTargets() []*target.Target {
 	allies := 5
 	return []*target.Target{
 		target.NewTarget(
 			target.Position{X: 0, Y: 10},
-			target.EnemyGroup{Type: target.EnemyTypeMech, Number: 1},
+			target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}},
 			nil,
 		),
 		target.NewTarget(
 			target.Position{X: 0, Y: 20},
-			target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 10},
+			target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
 			nil,
 		),
 		target.NewTarget(
 			target.Position{X: 0, Y: 30},
-			target.EnemyGroup{Type: target.EnemyTypeSoldier, Number: 20},
+			target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 20}},
 			&allies,
 		),
 	}
@@ -140,6 +187,39 @@ func TestFurthestEnemiesProtocol(t *testing.T) {
 	}
 }
 
+func TestMostEnemiesProtocol(t *testing.T) {
+	p := NewMostEnemiesProtocol()
+	targets := createTestTargets()
+
+	result, err := p.Apply(targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result) != 1 || result[0].EnemyCount() != 20 {
+		t.Errorf("Expected the target with 20 enemies, got %+v", result)
+	}
+}
+
+func TestLeastEnemiesProtocol(t *testing.T) {
+	p := NewLeastEnemiesProtocol()
+	targets := append(createTestTargets(), target.NewTarget(
+		target.Position{X: 0, Y: 40},
+		target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 1}},
+		nil,
+	))
+
+	result, err := p.Apply(targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Both single-enemy targets tie and keep scan order
+	if len(result) != 2 || result[0] != targets[0] || result[1] != targets[3] {
+		t.Errorf("Expected the two single-enemy targets in scan order, got %+v", result)
+	}
+}
+
 func TestAssistAlliesProtocol(t *testing.T) {
 	p := NewAssistAlliesProtocol()
 	targets := createTestTargets()
@@ -219,3 +299,130 @@ func TestCreateProtocolChain(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyProtocolChainTrace(t *testing.T) {
+	chain, err := CreateProtocolChain([]string{"closest-enemies", "avoid-mech"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	targets := createTestTargets()
+	result, steps, err := ApplyProtocolChainTrace(chain, targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result) != 1 || result[0] != targets[1] {
+		t.Fatalf("Expected the closest soldier target, got %+v", result)
+	}
+
+	wantSteps := []struct {
+		protocol string
+		input    int
+		output   int
+	}{
+		{protocol: "avoid-mech", input: 3, output: 2},
+		{protocol: "closest-enemies", input: 2, output: 1},
+	}
+
+	if len(steps) != len(wantSteps) {
+		t.Fatalf("Expected %d steps, got %d", len(wantSteps), len(steps))
+	}
+
+	for i, want := range wantSteps {
+		got := steps[i]
+		if got.Protocol != want.protocol || len(got.Input) != want.input || len(got.Output) != want.output {
+			t.Errorf("Step %d = %s %d->%d, want %s %d->%d",
+				i, got.Protocol, len(got.Input), len(got.Output), want.protocol, want.input, want.output)
+		}
+	}
+}
+
+func TestApplyProtocolChainTrace_NoSurvivors(t *testing.T) {
+	chain, err := CreateProtocolChain([]string{"avoid-mech"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	targets := createTestTargets()[:1]
+	_, steps, err := ApplyProtocolChainTrace(chain, targets)
+	if err == nil {
+		t.Fatal("Expected error when no targets survive")
+	}
+
+	if len(steps) != 1 || len(steps[0].Output) != 0 {
+		t.Errorf("Expected the eliminating step to be recorded, got %+v", steps)
+	}
+}
+
+func TestClosestAndFurthestEnemiesProtocol_MovedStation(t *testing.T) {
+	station := target.Station{Origin: target.Position{X: 0, Y: 30}}
+	targets := []*target.Target{
+		station.NewTarget(target.Position{X: 0, Y: 0}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}}, nil),
+		station.NewTarget(target.Position{X: 0, Y: 25}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}}, nil),
+	}
+
+	closest, err := NewClosestEnemiesProtocol().Apply(targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(closest) != 1 || closest[0].Coordinates != (target.Position{X: 0, Y: 25}) {
+		t.Errorf("Expected closest target at (0,25) from the moved station, got %+v", closest)
+	}
+
+	furthest, err := NewFurthestEnemiesProtocol().Apply(targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(furthest) != 1 || furthest[0].Coordinates != (target.Position{X: 0, Y: 0}) {
+		t.Errorf("Expected furthest target at (0,0) from the moved station, got %+v", furthest)
+	}
+}
+
+func TestRankTargets(t *testing.T) {
+	targets := createTestTargets()
+
+	tests := []struct {
+		name      string
+		protocols []string
+		want      []*target.Target
+	}{
+		{
+			name:      "closest first",
+			protocols: []string{"closest-enemies"},
+			want:      []*target.Target{targets[0], targets[1], targets[2]},
+		},
+		{
+			name:      "furthest first",
+			protocols: []string{"furthest-enemies"},
+			want:      []*target.Target{targets[2], targets[1], targets[0]},
+		},
+		{
+			name:      "validation protocols drop targets",
+			protocols: []string{"avoid-mech", "most-enemies"},
+			want:      []*target.Target{targets[2], targets[1]},
+		},
+		{
+			name:      "preferred type ranks first",
+			protocols: []string{"prioritize-mech", "furthest-enemies"},
+			want:      []*target.Target{targets[0], targets[2], targets[1]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := CreateProtocolChain(tt.protocols)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got, err := RankTargets(chain, targets, DefaultTieBreaker)
+			if err != nil {
+				t.Fatalf("RankTargets() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RankTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package protocol
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tier determines where a protocol runs in the chain. Lower tiers run first.
+type Tier int
+
+const (
+	// TierValidation protocols discard targets that must never be attacked
+	TierValidation Tier = iota
+	// TierType protocols narrow targets down by enemy type
+	TierType
+	// TierCount protocols narrow targets down by their number of enemies.
+	// They run before TierPosition so distance only separates targets with
+	// the same count, whatever order the protocols are requested in.
+	TierCount
+	// TierPosition protocols narrow targets down by their position
+	TierPosition
+	// TierTactical protocols apply the final tactical preferences
+	TierTactical
+)
+
+// String returns the tier name
+func (t Tier) String() string {
+	switch t {
+	case TierValidation:
+		return "validation"
+	case TierType:
+		return "type"
+	case TierCount:
+		return "count"
+	case TierPosition:
+		return "position"
+	case TierTactical:
+		return "tactical"
+	default:
+		return fmt.Sprintf("tier(%d)", int(t))
+	}
+}
+
+// ParseTier returns the tier with the given name
+func ParseTier(name string) (Tier, error) {
+	for t := TierValidation; t <= TierTactical; t++ {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown tier %q", ErrInvalidDefinition, name)
+}
+
+// Definition declares a protocol to the registry
+type Definition struct {
+	Name      string
+	Tier      Tier
+	Conflicts []string
+	New       func() Protocol
+}
+
+// Registry holds the set of known protocols
+type Registry struct {
+	definitions map[string]Definition
+	mu          sync.RWMutex
+}
+
+// NewRegistry creates an empty protocol registry
+func NewRegistry() *Registry {
+	return &Registry{
+		definitions: make(map[string]Definition),
+	}
+}
+
+// defaultRegistry holds the built-in protocols and anything added via Register
+var defaultRegistry = newBuiltinRegistry()
+
+// newBuiltinRegistry creates a registry with the built-in protocols
+func newBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	for _, def := range []Definition{
+		{Name: "avoid-mech", Tier: TierValidation, New: func() Protocol { return NewAvoidMechProtocol() }},
+		{Name: "avoid-crossfire", Tier: TierValidation, New: func() Protocol { return NewAvoidCrossfireProtocol() }},
+		{Name: "prioritize-mech", Tier: TierType, New: func() Protocol { return NewPrioritizeMechProtocol() }},
+		{Name: "closest-enemies", Tier: TierPosition, Conflicts: []string{"furthest-enemies"}, New: func() Protocol { return NewClosestEnemiesProtocol() }},
+		{Name: "furthest-enemies", Tier: TierPosition, Conflicts: []string{"closest-enemies"}, New: func() Protocol { return NewFurthestEnemiesProtocol() }},
+		{Name: "most-enemies", Tier: TierCount, Conflicts: []string{"least-enemies"}, New: func() Protocol { return NewMostEnemiesProtocol() }},
+		{Name: "least-enemies", Tier: TierCount, Conflicts: []string{"most-enemies"}, New: func() Protocol { return NewLeastEnemiesProtocol() }},
+		{Name: "assist-allies", Tier: TierTactical, New: func() Protocol { return NewAssistAlliesProtocol() }},
+	} {
+		if err := r.Register(def); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// DefaultRegistry returns the registry used by ValidateProtocols and CreateProtocolChain
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a protocol to the default registry
+func Register(def Definition) error {
+	return defaultRegistry.Register(def)
+}
+
+// Register adds a protocol definition to the registry
+func (r *Registry) Register(def Definition) error {
+	if def.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidDefinition)
+	}
+	if def.New == nil {
+		return fmt.Errorf("%w: %s has no constructor", ErrInvalidDefinition, def.Name)
+	}
+	if def.Tier < TierValidation || def.Tier > TierTactical {
+		return fmt.Errorf("%w: %s has invalid tier %d", ErrInvalidDefinition, def.Name, def.Tier)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.definitions[def.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateProtocol, def.Name)
+	}
+	r.definitions[def.Name] = def
+	return nil
+}
+
+// Lookup returns the definition registered under name
+func (r *Registry) Lookup(name string) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.definitions[name]
+	return def, ok
+}
+
+// Names returns the sorted names of all registered protocols
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.definitions))
+	for name := range r.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Combination returns a stable name for a set of protocols: registered names
+// sorted, deduplicated and joined with "+". Unregistered names collapse into
+// "unknown" so the result is bounded by the registry, e.g. for metric labels.
+func (r *Registry) Combination(protocols []string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(protocols))
+	for _, name := range protocols {
+		if _, ok := r.definitions[name]; !ok {
+			name = "unknown"
+		}
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	names = slices.Compact(names)
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "+")
+}
+
+// Validate checks that every protocol is registered and no two of them conflict.
+// Conflicts are symmetric: declaring a conflict on either side is enough.
+func (r *Registry) Validate(protocols []string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i, name := range protocols {
+		def, ok := r.definitions[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidProtocol, name)
+		}
+
+		for _, other := range protocols[:i] {
+			if r.conflicts(def, other) {
+				return fmt.Errorf("%w: %s and %s", ErrIncompatibleProtocols, other, name)
+			}
+		}
+	}
+	return nil
+}
+
+// conflicts reports whether def and the protocol named other exclude each other
+func (r *Registry) conflicts(def Definition, other string) bool {
+	for _, c := range def.Conflicts {
+		if c == other {
+			return true
+		}
+	}
+	for _, c := range r.definitions[other].Conflicts {
+		if c == def.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateChain validates the protocols and instantiates them ordered by tier.
+// Protocols in the same tier keep the order in which they were requested.
+func (r *Registry) CreateChain(protocols []string) ([]Protocol, error) {
+	if err := r.Validate(protocols); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defs := make([]Definition, 0, len(protocols))
+	for _, name := range protocols {
+		defs = append(defs, r.definitions[name])
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(defs, func(i, j int) bool {
+		return defs[i].Tier < defs[j].Tier
+	})
+
+	chain := make([]Protocol, 0, len(defs))
+	for _, def := range defs {
+		chain = append(chain, def.New())
+	}
+	return chain, nil
+}
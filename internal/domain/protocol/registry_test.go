@@ -0,0 +1,175 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// namedProtocol is a pass-through protocol used to exercise the registry
+type namedProtocol struct {
+	name string
+}
+
+func (p *namedProtocol) Name() string {
+	return p.name
+}
+
+func (p *namedProtocol) Apply(targets []*target.Target) ([]*target.Target, error) {
+	return targets, nil
+}
+
+func newTestDefinition(name string, tier Tier, conflicts ...string) Definition {
+	return Definition{
+		Name:      name,
+		Tier:      tier,
+		Conflicts: conflicts,
+		New:       func() Protocol { return &namedProtocol{name: name} },
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     Definition
+		wantErr bool
+	}{
+		{
+			name: "valid definition",
+			def:  newTestDefinition("hold-fire", TierTactical),
+		},
+		{
+			name:    "duplicate name",
+			def:     newTestDefinition("avoid-mech", TierValidation),
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			def:     newTestDefinition("", TierValidation),
+			wantErr: true,
+		},
+		{
+			name:    "missing constructor",
+			def:     Definition{Name: "no-constructor", Tier: TierValidation},
+			wantErr: true,
+		},
+		{
+			name:    "invalid tier",
+			def:     newTestDefinition("bad-tier", Tier(42)),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newBuiltinRegistry()
+			err := r.Register(tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Registry.Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_CreateChain(t *testing.T) {
+	r := newBuiltinRegistry()
+	for _, def := range []Definition{
+		newTestDefinition("scout-first", TierValidation),
+		newTestDefinition("flank-left", TierPosition, "closest-enemies"),
+		newTestDefinition("regroup", TierTactical),
+	} {
+		if err := r.Register(def); err != nil {
+			t.Fatalf("Failed to register %s: %v", def.Name, err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		protocols []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "custom protocols ordered by tier",
+			protocols: []string{"regroup", "flank-left", "avoid-mech", "scout-first"},
+			want:      []string{"avoid-mech", "scout-first", "flank-left", "regroup"},
+		},
+		{
+			name:      "declared conflict",
+			protocols: []string{"flank-left", "closest-enemies"},
+			wantErr:   true,
+		},
+		{
+			name:      "conflict declared by the other protocol only",
+			protocols: []string{"closest-enemies", "flank-left"},
+			wantErr:   true,
+		},
+		{
+			name:      "unregistered protocol",
+			protocols: []string{"regroup", "retreat"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.CreateChain(tt.protocols)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Registry.CreateChain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				var gotNames []string
+				for _, p := range got {
+					gotNames = append(gotNames, p.Name())
+				}
+				if !reflect.DeepEqual(gotNames, tt.want) {
+					t.Errorf("Registry.CreateChain() = %v, want %v", gotNames, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_Combination(t *testing.T) {
+	tests := []struct {
+		name      string
+		protocols []string
+		want      string
+	}{
+		{
+			name:      "single protocol",
+			protocols: []string{"avoid-mech"},
+			want:      "avoid-mech",
+		},
+		{
+			name:      "order independent",
+			protocols: []string{"prioritize-mech", "closest-enemies", "avoid-crossfire"},
+			want:      "avoid-crossfire+closest-enemies+prioritize-mech",
+		},
+		{
+			name:      "duplicates collapse",
+			protocols: []string{"avoid-mech", "avoid-mech"},
+			want:      "avoid-mech",
+		},
+		{
+			name:      "unregistered names are bounded",
+			protocols: []string{"made-up", "also-made-up", "avoid-mech"},
+			want:      "avoid-mech+unknown",
+		},
+		{
+			name: "empty",
+			want: "none",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRegistry().Combination(tt.protocols); got != tt.want {
+				t.Errorf("Registry.Combination() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
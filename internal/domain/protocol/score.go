@@ -0,0 +1,165 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// Scorer is implemented by protocols that can rank targets instead of
+// filtering them. Score returns one score per target in the same order,
+// each between -1 (avoid) and 1 (prefer).
+type Scorer interface {
+	Score(targets []*target.Target) []float64
+}
+
+// Scored pairs a target with its weighted score
+type Scored struct {
+	Target *target.Target
+	Score  float64
+}
+
+// ScoreTargets sums the weighted scores every protocol in the chain gives
+// each target and returns the targets ordered from highest to lowest score.
+// Validation tier protocols are never traded off against a score: they
+// filter the targets first, and only the survivors are scored by the rest of
+// the chain. Protocols missing from weights count with weight 1. Ties keep
+// their original order.
+func ScoreTargets(chain []Protocol, weights map[string]float64, targets []*target.Target, opts ...ChainOption) ([]Scored, error) {
+	if len(targets) == 0 {
+		return nil, target.ErrNoValidTargets
+	}
+
+	obs := newChainConfig(opts).observer
+	obs.TargetsSelected(chainName(chain), targets)
+
+	filters, scorers := splitValidation(chain)
+	targets, err := applyChain(filters, targets, obs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scored := make([]Scored, len(targets))
+	for i, t := range targets {
+		scored[i].Target = t
+	}
+
+	for _, p := range scorers {
+		scorer, ok := p.(Scorer)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNotScorable, p.Name())
+		}
+
+		weight, ok := weights[p.Name()]
+		if !ok {
+			weight = 1
+		}
+
+		start := time.Now()
+		scores := scorer.Score(targets)
+		obs.ProtocolApplied(p.Name(), time.Since(start), targets, targets)
+
+		for i, s := range scores {
+			scored[i].Score += weight * s
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+	return scored, nil
+}
+
+// IsValidation reports whether name is registered as a validation tier protocol
+func IsValidation(name string) bool {
+	def, ok := defaultRegistry.Lookup(name)
+	return ok && def.Tier == TierValidation
+}
+
+// splitValidation separates the validation tier protocols of the chain from
+// the rest, keeping their order
+func splitValidation(chain []Protocol) (validation, rest []Protocol) {
+	for _, p := range chain {
+		if IsValidation(p.Name()) {
+			validation = append(validation, p)
+		} else {
+			rest = append(rest, p)
+		}
+	}
+	return validation, rest
+}
+
+// Score implements Scorer by favouring mech targets
+func (p *PrioritizeMechProtocol) Score(targets []*target.Target) []float64 {
+	return scoreEach(targets, func(t *target.Target) float64 {
+		return boolScore(t.IsMech())
+	})
+}
+
+// Score implements Scorer by favouring targets near the station, from 1 at
+// the station to 0 at the edge of its range
+func (p *ClosestEnemiesProtocol) Score(targets []*target.Target) []float64 {
+	return scoreEach(targets, func(t *target.Target) float64 {
+		return 1 - t.Distance()/t.MaxRange()
+	})
+}
+
+// Score implements Scorer by favouring targets far from the station, from 0
+// at the station to 1 at the edge of its range
+func (p *FurthestEnemiesProtocol) Score(targets []*target.Target) []float64 {
+	return scoreEach(targets, func(t *target.Target) float64 {
+		return t.Distance() / t.MaxRange()
+	})
+}
+
+// Score implements Scorer by favouring targets with allies
+func (p *AssistAlliesProtocol) Score(targets []*target.Target) []float64 {
+	return scoreEach(targets, func(t *target.Target) float64 {
+		return boolScore(t.HasAllies())
+	})
+}
+
+// Score implements Scorer by favouring targets with more enemies, scaled so
+// the largest group scores 1
+func (p *MostEnemiesProtocol) Score(targets []*target.Target) []float64 {
+	maxEnemies := 0
+	for _, t := range targets {
+		maxEnemies = max(maxEnemies, t.EnemyCount())
+	}
+
+	return scoreEach(targets, func(t *target.Target) float64 {
+		return float64(t.EnemyCount()) / float64(maxEnemies)
+	})
+}
+
+// Score implements Scorer by favouring targets with fewer enemies, scaled so
+// the smallest group scores 1
+func (p *LeastEnemiesProtocol) Score(targets []*target.Target) []float64 {
+	minEnemies := targets[0].EnemyCount()
+	for _, t := range targets[1:] {
+		minEnemies = min(minEnemies, t.EnemyCount())
+	}
+
+	return scoreEach(targets, func(t *target.Target) float64 {
+		return float64(minEnemies) / float64(t.EnemyCount())
+	})
+}
+
+// scoreEach scores every target independently
+func scoreEach(targets []*target.Target, score func(*target.Target) float64) []float64 {
+	scores := make([]float64, len(targets))
+	for i, t := range targets {
+		scores[i] = score(t)
+	}
+	return scores
+}
+
+// boolScore converts a yes/no criterion into a score
+func boolScore(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package protocol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+func TestScoreTargets(t *testing.T) {
+	station := target.Station{}
+	nearSoldiers := station.NewTarget(target.Position{X: 0, Y: 10}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}}, nil)
+	farMech := station.NewTarget(target.Position{X: 0, Y: 90}, target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}}, nil)
+	midMech := station.NewTarget(target.Position{X: 0, Y: 40}, target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}}, nil)
+	targets := []*target.Target{nearSoldiers, farMech, midMech}
+
+	chain := []Protocol{NewPrioritizeMechProtocol(), NewClosestEnemiesProtocol()}
+
+	tests := []struct {
+		name    string
+		weights map[string]float64
+		want    []*target.Target
+	}{
+		{
+			name: "equal weights prefer a mech that is not too far",
+			want: []*target.Target{midMech, farMech, nearSoldiers},
+		},
+		{
+			name:    "distance outweighs mech",
+			weights: map[string]float64{"prioritize-mech": 0.5, "closest-enemies": 2},
+			want:    []*target.Target{nearSoldiers, midMech, farMech},
+		},
+		{
+			name:    "zero weight ignores distance",
+			weights: map[string]float64{"closest-enemies": 0},
+			want:    []*target.Target{farMech, midMech, nearSoldiers},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scored, err := ScoreTargets(chain, tt.weights, targets)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for i, s := range scored {
+				if s.Target != tt.want[i] {
+					t.Errorf("Position %d: got target at %+v (score %g), want %+v",
+						i, s.Target.Coordinates, s.Score, tt.want[i].Coordinates)
+				}
+			}
+		})
+	}
+}
+
+func TestScoreTargets_EnemyCount(t *testing.T) {
+	targets := []*target.Target{
+		target.NewTarget(target.Position{X: 0, Y: 10}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 5}}, nil),
+		target.NewTarget(target.Position{X: 0, Y: 60}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 40}}, nil),
+	}
+
+	// Forty enemies at 60 km are worth more than five at 10 km
+	chain := []Protocol{NewMostEnemiesProtocol(), NewClosestEnemiesProtocol()}
+	scored, err := ScoreTargets(chain, nil, targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if scored[0].Target != targets[1] {
+		t.Errorf("Expected most-enemies to favour the large group, got %+v", scored[0].Target.Coordinates)
+	}
+
+	chain = []Protocol{NewLeastEnemiesProtocol(), NewClosestEnemiesProtocol()}
+	scored, err = ScoreTargets(chain, nil, targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if scored[0].Target != targets[0] {
+		t.Errorf("Expected least-enemies to favour the small group, got %+v", scored[0].Target.Coordinates)
+	}
+}
+
+func TestScoreTargets_Errors(t *testing.T) {
+	station := target.Station{}
+	targets := []*target.Target{
+		station.NewTarget(target.Position{X: 0, Y: 10}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}}, nil),
+	}
+
+	if _, err := ScoreTargets([]Protocol{NewClosestEnemiesProtocol()}, nil, nil); !errors.Is(err, target.ErrNoValidTargets) {
+		t.Errorf("Expected ErrNoValidTargets without targets, got %v", err)
+	}
+
+	// Embedding the interface hides the Score method
+	p := struct{ Protocol }{NewClosestEnemiesProtocol()}
+	if _, err := ScoreTargets([]Protocol{p}, nil, targets); !errors.Is(err, ErrNotScorable) {
+		t.Errorf("Expected ErrNotScorable for a filter-only protocol, got %v", err)
+	}
+}
+
+func TestScoreTargets_Validation(t *testing.T) {
+	allies := 2
+	station := target.Station{}
+	nearMech := station.NewTarget(target.Position{X: 0, Y: 5}, target.EnemyGroups{{Type: target.EnemyTypeMech, Number: 1}}, &allies)
+	farSoldiers := station.NewTarget(target.Position{X: 0, Y: 90}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}}, nil)
+	targets := []*target.Target{nearMech, farSoldiers}
+
+	// However well the mech scores, avoid-crossfire never lets it through
+	chain := []Protocol{NewAvoidCrossfireProtocol(), NewPrioritizeMechProtocol(), NewClosestEnemiesProtocol()}
+	weights := map[string]float64{"prioritize-mech": 10}
+	scored, err := ScoreTargets(chain, weights, targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(scored) != 1 || scored[0].Target != farSoldiers {
+		t.Errorf("Expected only the target without allies to be scored, got %+v", scored)
+	}
+
+	chain = []Protocol{NewAvoidCrossfireProtocol(), NewClosestEnemiesProtocol()}
+	if _, err := ScoreTargets(chain, nil, []*target.Target{nearMech}); !errors.Is(err, target.ErrNoValidTargets) {
+		t.Errorf("Expected ErrNoValidTargets when validation discards every target, got %v", err)
+	}
+}
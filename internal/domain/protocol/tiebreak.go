@@ -0,0 +1,75 @@
+package protocol
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// TieBreaker orders targets the protocols could not tell apart, so that
+// identical scans always yield the same target
+type TieBreaker string
+
+const (
+	// TieBreakScanOrder keeps targets in the order they appear in the scan
+	TieBreakScanOrder TieBreaker = "scan-order"
+	// TieBreakMostEnemies prefers the target with the most enemies
+	TieBreakMostEnemies TieBreaker = "most-enemies"
+	// TieBreakFewestAllies prefers the target with the fewest allies
+	TieBreakFewestAllies TieBreaker = "fewest-allies"
+	// TieBreakLowestAngle prefers the target with the lowest bearing from the
+	// station, counterclockwise from the positive x axis
+	TieBreakLowestAngle TieBreaker = "lowest-angle"
+)
+
+// DefaultTieBreaker is the policy used when none is configured
+const DefaultTieBreaker = TieBreakScanOrder
+
+// TieBreakers lists every supported policy
+var TieBreakers = []TieBreaker{TieBreakScanOrder, TieBreakMostEnemies, TieBreakFewestAllies, TieBreakLowestAngle}
+
+// ParseTieBreaker validates a policy name; an empty name selects the default
+func ParseTieBreaker(name string) (TieBreaker, error) {
+	if name == "" {
+		return DefaultTieBreaker, nil
+	}
+
+	tb := TieBreaker(name)
+	if !slices.Contains(TieBreakers, tb) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTieBreaker, name)
+	}
+	return tb, nil
+}
+
+// Compare orders two targets by the policy. Targets it considers equal
+// compare as 0 and keep their scan order under a stable sort.
+func (tb TieBreaker) Compare(a, b *target.Target) int {
+	switch tb {
+	case TieBreakMostEnemies:
+		return cmp.Compare(b.EnemyCount(), a.EnemyCount())
+	case TieBreakFewestAllies:
+		return cmp.Compare(allies(a), allies(b))
+	case TieBreakLowestAngle:
+		return cmp.Compare(a.Angle(), b.Angle())
+	default:
+		return 0
+	}
+}
+
+// BreakTies returns the targets ordered by the policy. Targets must be in
+// scan order, which the protocol chain preserves.
+func BreakTies(tb TieBreaker, targets []*target.Target) []*target.Target {
+	sorted := slices.Clone(targets)
+	slices.SortStableFunc(sorted, tb.Compare)
+	return sorted
+}
+
+// allies returns the number of allies at a target
+func allies(t *target.Target) int {
+	if t.Allies == nil {
+		return 0
+	}
+	return *t.Allies
+}
@@ -0,0 +1,58 @@
+package protocol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+func TestBreakTies(t *testing.T) {
+	two, five := 2, 5
+	station := target.Station{}
+	// All three targets are 50 km from the station
+	north := station.NewTarget(target.Position{X: 0, Y: 50}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}}, &five)
+	west := station.NewTarget(target.Position{X: -50, Y: 0}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 30}}, &two)
+	east := station.NewTarget(target.Position{X: 50, Y: 0}, target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 30}}, nil)
+	scan := []*target.Target{north, west, east}
+
+	tests := []struct {
+		tieBreaker TieBreaker
+		want       []*target.Target
+	}{
+		{TieBreakScanOrder, []*target.Target{north, west, east}},
+		{TieBreakMostEnemies, []*target.Target{west, east, north}},
+		{TieBreakFewestAllies, []*target.Target{east, west, north}},
+		{TieBreakLowestAngle, []*target.Target{east, north, west}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.tieBreaker), func(t *testing.T) {
+			tied, err := NewClosestEnemiesProtocol().Apply(scan)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := BreakTies(tt.tieBreaker, tied)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Position %d: got %+v, want %+v", i, got[i].Coordinates, tt.want[i].Coordinates)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTieBreaker(t *testing.T) {
+	if tb, err := ParseTieBreaker(""); err != nil || tb != DefaultTieBreaker {
+		t.Errorf("ParseTieBreaker(\"\") = %q, %v, want default", tb, err)
+	}
+
+	if tb, err := ParseTieBreaker("lowest-angle"); err != nil || tb != TieBreakLowestAngle {
+		t.Errorf("ParseTieBreaker(lowest-angle) = %q, %v", tb, err)
+	}
+
+	if _, err := ParseTieBreaker("coin-flip"); !errors.Is(err, ErrInvalidTieBreaker) {
+		t.Errorf("Expected ErrInvalidTieBreaker, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package target
+
+import "errors"
+
+var (
+	// ErrNoValidTargets is returned when no target is left to attack
+	ErrNoValidTargets = errors.New("no valid targets")
+	// ErrNoEnemies is returned for a scan point that reports no enemy groups
+	ErrNoEnemies = errors.New("no enemy groups")
+	// ErrInvalidEnemyType is returned for an unknown enemy type
+	ErrInvalidEnemyType = errors.New("invalid enemy type")
+	// ErrInvalidEnemyNumber is returned for a non-positive enemy count
+	ErrInvalidEnemyNumber = errors.New("invalid enemy number")
+	// ErrInvalidAllies is returned for a negative ally count
+	ErrInvalidAllies = errors.New("invalid allies number")
+	// ErrInvalidZone is returned for a no-fire zone without a usable shape
+	ErrInvalidZone = errors.New("invalid no-fire zone")
+	// ErrInvalidRange is returned for a negative station engagement range
+	ErrInvalidRange = errors.New("invalid station max range")
+)
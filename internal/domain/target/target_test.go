@@ -1,7 +1,9 @@
 package target
 
 import (
+	"encoding/json"
 	"math"
+	"reflect"
 	"testing"
 )
 
@@ -53,7 +55,7 @@ func TestTarget_IsValid(t *testing.T) {
 			name: "within range",
 			target: NewTarget(
 				Position{X: 60, Y: 80},
-				EnemyGroup{Type: EnemyTypeSoldier, Number: 10},
+				EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}},
 				nil,
 			),
 			want: true,
@@ -62,7 +64,7 @@ func TestTarget_IsValid(t *testing.T) {
 			name: "at range limit",
 			target: NewTarget(
 				Position{X: 80, Y: 60},
-				EnemyGroup{Type: EnemyTypeSoldier, Number: 10},
+				EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}},
 				nil,
 			),
 			want: true,
@@ -71,7 +73,7 @@ func TestTarget_IsValid(t *testing.T) {
 			name: "out of range",
 			target: NewTarget(
 				Position{X: 80, Y: 80},
-				EnemyGroup{Type: EnemyTypeSoldier, Number: 10},
+				EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}},
 				nil,
 			),
 			want: false,
@@ -98,7 +100,7 @@ func TestTarget_HasAllies(t *testing.T) {
 			name: "with allies",
 			target: NewTarget(
 				Position{X: 0, Y: 0},
-				EnemyGroup{Type: EnemyTypeSoldier, Number: 10},
+				EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}},
 				&allies,
 			),
 			want: true,
@@ -107,7 +109,7 @@ func TestTarget_HasAllies(t *testing.T) {
 			name: "without allies",
 			target: NewTarget(
 				Position{X: 0, Y: 0},
-				EnemyGroup{Type: EnemyTypeSoldier, Number: 10},
+				EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}},
 				nil,
 			),
 			want: false,
@@ -116,7 +118,7 @@ func TestTarget_HasAllies(t *testing.T) {
 			name: "zero allies",
 			target: NewTarget(
 				Position{X: 0, Y: 0},
-				EnemyGroup{Type: EnemyTypeSoldier, Number: 10},
+				EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}},
 				new(int), // zero value
 			),
 			want: false,
@@ -142,7 +144,7 @@ func TestTarget_IsMech(t *testing.T) {
 			name: "is mech",
 			target: NewTarget(
 				Position{X: 0, Y: 0},
-				EnemyGroup{Type: EnemyTypeMech, Number: 1},
+				EnemyGroups{{Type: EnemyTypeMech, Number: 1}},
 				nil,
 			),
 			want: true,
@@ -151,11 +153,20 @@ func TestTarget_IsMech(t *testing.T) {
 			name: "is soldier",
 			target: NewTarget(
 				Position{X: 0, Y: 0},
-				EnemyGroup{Type: EnemyTypeSoldier, Number: 10},
+				EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}},
 				nil,
 			),
 			want: false,
 		},
+		{
+			name: "soldiers with a mech",
+			target: NewTarget(
+				Position{X: 0, Y: 0},
+				EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}, {Type: EnemyTypeMech, Number: 1}},
+				nil,
+			),
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,3 +177,199 @@ func TestTarget_IsMech(t *testing.T) {
 		})
 	}
 }
+
+func TestEnemyGroups_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    EnemyGroups
+		wantErr bool
+	}{
+		{
+			name: "legacy single group",
+			data: `{"type": "mech", "number": 1}`,
+			want: EnemyGroups{{Type: EnemyTypeMech, Number: 1}},
+		},
+		{
+			name: "list of groups",
+			data: `[{"type": "soldier", "number": 10}, {"type": "mech", "number": 2}]`,
+			want: EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}, {Type: EnemyTypeMech, Number: 2}},
+		},
+		{
+			name: "empty list",
+			data: `[]`,
+			want: EnemyGroups{},
+		},
+		{
+			name:    "invalid shape",
+			data:    `"mech"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got EnemyGroups
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnemyGroups_Total(t *testing.T) {
+	groups := EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}, {Type: EnemyTypeMech, Number: 2}, {Type: EnemyTypeSoldier, Number: 5}}
+
+	if got := groups.Total(); got != 17 {
+		t.Errorf("Total() = %d, want 17", got)
+	}
+	if got := groups.Types(); !reflect.DeepEqual(got, []EnemyType{EnemyTypeSoldier, EnemyTypeMech}) {
+		t.Errorf("Types() = %v, want [soldier mech]", got)
+	}
+}
+
+func TestAssessCollateral(t *testing.T) {
+	allies := 2
+	near := NewTarget(Position{X: 0, Y: 10}, EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}}, nil)
+	far := NewTarget(Position{X: 0, Y: 40}, EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}}, nil)
+	withAllies := NewTarget(Position{X: 0, Y: 80}, EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}}, &allies)
+	allied := []Position{{X: 5, Y: 10}}
+
+	AssessCollateral([]*Target{near, far, withAllies}, allied, 5)
+
+	if !near.EndangersAllies() {
+		t.Error("Expected target 5 km from allies to endanger them")
+	}
+	if far.EndangersAllies() {
+		t.Error("Expected target outside the blast radius to be safe")
+	}
+	if !withAllies.EndangersAllies() {
+		t.Error("Expected target with its own allies to endanger them")
+	}
+
+	// A smaller radius clears the earlier assessment
+	AssessCollateral([]*Target{near}, allied, 4)
+	if near.EndangersAllies() {
+		t.Error("Expected reassessment with a smaller radius to clear the target")
+	}
+}
+
+func TestPosition_DistanceTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		position Position
+		other    Position
+		want     float64
+	}{
+		{
+			name:     "same position",
+			position: Position{X: 7, Y: -2},
+			other:    Position{X: 7, Y: -2},
+			want:     0,
+		},
+		{
+			name:     "shifted origin",
+			position: Position{X: 13, Y: 14},
+			other:    Position{X: 10, Y: 10},
+			want:     5,
+		},
+		{
+			name:     "negative origin",
+			position: Position{X: 0, Y: 0},
+			other:    Position{X: -6, Y: -8},
+			want:     10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.position.DistanceTo(tt.other)
+			if math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("Position.DistanceTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosition_AngleFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		position Position
+		other    Position
+		want     float64
+	}{
+		{
+			name:     "east",
+			position: Position{X: 5, Y: 0},
+			want:     0,
+		},
+		{
+			name:     "north",
+			position: Position{X: 0, Y: 5},
+			want:     90,
+		},
+		{
+			name:     "south of shifted origin",
+			position: Position{X: 10, Y: 4},
+			other:    Position{X: 10, Y: 10},
+			want:     270,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.position.AngleFrom(tt.other)
+			if math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("Position.AngleFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStation_NewTarget(t *testing.T) {
+	tests := []struct {
+		name         string
+		station      Station
+		coords       Position
+		wantDistance float64
+		wantValid    bool
+	}{
+		{
+			name:         "default station",
+			station:      DefaultStation(),
+			coords:       Position{X: 60, Y: 80},
+			wantDistance: 100,
+			wantValid:    true,
+		},
+		{
+			name:         "moved station brings target into range",
+			station:      Station{Origin: Position{X: 100, Y: 100}},
+			coords:       Position{X: 130, Y: 140},
+			wantDistance: 50,
+			wantValid:    true,
+		},
+		{
+			name:         "custom range",
+			station:      Station{Origin: Position{X: 100, Y: 100}, MaxRange: 40},
+			coords:       Position{X: 130, Y: 140},
+			wantDistance: 50,
+			wantValid:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.station.NewTarget(tt.coords, EnemyGroups{{Type: EnemyTypeSoldier, Number: 10}}, nil)
+			if math.Abs(target.Distance()-tt.wantDistance) > 0.0001 {
+				t.Errorf("Target.Distance() = %v, want %v", target.Distance(), tt.wantDistance)
+			}
+			if got := target.IsValid(); got != tt.wantValid {
+				t.Errorf("Target.IsValid() = %v, want %v", got, tt.wantValid)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package target
 
-import "math"
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"slices"
+)
 
 // Position represents x,y coordinates
 type Position struct {
@@ -10,7 +15,55 @@ type Position struct {
 
 // Distance calculates the distance from origin (0,0)
 func (p Position) Distance() float64 {
-	return math.Sqrt(float64(p.X*p.X + p.Y*p.Y))
+	return p.DistanceTo(Position{})
+}
+
+// DistanceTo calculates the distance from p to another position
+func (p Position) DistanceTo(other Position) float64 {
+	dx := float64(p.X - other.X)
+	dy := float64(p.Y - other.Y)
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// AngleFrom returns the bearing of p seen from another position in degrees,
+// counterclockwise from the positive x axis, in [0, 360)
+func (p Position) AngleFrom(other Position) float64 {
+	deg := math.Atan2(float64(p.Y-other.Y), float64(p.X-other.X)) * 180 / math.Pi
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// DefaultMaxRange is the engagement range in km of a station with no explicit range
+const DefaultMaxRange = 100
+
+// Station describes where the battle station is and how far it can engage
+type Station struct {
+	Origin   Position `json:"origin"`
+	MaxRange float64  `json:"max_range,omitempty"`
+}
+
+// DefaultStation returns a station at (0,0) with the default engagement range
+func DefaultStation() Station {
+	return Station{MaxRange: DefaultMaxRange}
+}
+
+// NewTarget creates a new Target with its distance measured from the station
+func (s Station) NewTarget(coords Position, enemies EnemyGroups, allies *int) *Target {
+	maxRange := s.MaxRange
+	if maxRange == 0 {
+		maxRange = DefaultMaxRange
+	}
+
+	return &Target{
+		Coordinates: coords,
+		Enemies:     enemies,
+		Allies:      allies,
+		distance:    coords.DistanceTo(s.Origin),
+		angle:       coords.AngleFrom(s.Origin),
+		maxRange:    maxRange,
+	}
 }
 
 // EnemyType represents the type of enemy (soldier or mech)
@@ -27,33 +80,111 @@ type EnemyGroup struct {
 	Number int       `json:"number"`
 }
 
+// EnemyGroups lists the enemy groups reported at a single scan point
+type EnemyGroups []EnemyGroup
+
+// IsSingleGroup reports whether data holds enemies in the single group
+// object form sent by older probe droids rather than a list of groups
+func IsSingleGroup(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// UnmarshalJSON accepts a list of groups or, for older probe droids, a single group object
+func (g *EnemyGroups) UnmarshalJSON(data []byte) error {
+	if IsSingleGroup(data) {
+		var group EnemyGroup
+		if err := json.Unmarshal(data, &group); err != nil {
+			return err
+		}
+		*g = EnemyGroups{group}
+		return nil
+	}
+
+	var groups []EnemyGroup
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return err
+	}
+	*g = groups
+	return nil
+}
+
+// Total returns the number of enemies across all groups
+func (g EnemyGroups) Total() int {
+	total := 0
+	for _, group := range g {
+		total += group.Number
+	}
+	return total
+}
+
+// Count returns the number of enemies of the given type
+func (g EnemyGroups) Count(t EnemyType) int {
+	count := 0
+	for _, group := range g {
+		if group.Type == t {
+			count += group.Number
+		}
+	}
+	return count
+}
+
+// Has reports whether any group is of the given type
+func (g EnemyGroups) Has(t EnemyType) bool {
+	return slices.ContainsFunc(g, func(group EnemyGroup) bool {
+		return group.Type == t
+	})
+}
+
+// Types returns the distinct enemy types in the order they were reported
+func (g EnemyGroups) Types() []EnemyType {
+	types := make([]EnemyType, 0, len(g))
+	for _, group := range g {
+		if !slices.Contains(types, group.Type) {
+			types = append(types, group.Type)
+		}
+	}
+	return types
+}
+
 // Target represents a potential target with its position and enemy information
 type Target struct {
-	Coordinates Position   `json:"coordinates"`
-	Enemies     EnemyGroup `json:"enemies"`
-	Allies      *int       `json:"allies,omitempty"`
-	distance    float64    // cached distance value
+	Coordinates Position    `json:"coordinates"`
+	Enemies     EnemyGroups `json:"enemies"`
+	Allies      *int        `json:"allies,omitempty"`
+	distance    float64     // cached distance value
+	angle       float64     // cached bearing from the station origin
+	maxRange    float64     // engagement range of the station
+
+	alliesInBlast bool // allies elsewhere in the scan are within the blast radius
 }
 
-// NewTarget creates a new Target and pre-calculates its distance
-func NewTarget(coords Position, enemies EnemyGroup, allies *int) *Target {
-	t := &Target{
-		Coordinates: coords,
-		Enemies:     enemies,
-		Allies:      allies,
-	}
-	t.distance = coords.Distance()
-	return t
+// NewTarget creates a new Target and pre-calculates its distance from (0,0)
+func NewTarget(coords Position, enemies EnemyGroups, allies *int) *Target {
+	return DefaultStation().NewTarget(coords, enemies, allies)
 }
 
-// Distance returns the pre-calculated distance from origin
+// Distance returns the pre-calculated distance from the station origin
 func (t *Target) Distance() float64 {
 	return t.distance
 }
 
-// IsValid checks if the target is within valid range (<=100km)
+// Angle returns the pre-calculated bearing from the station origin in degrees
+func (t *Target) Angle() float64 {
+	return t.angle
+}
+
+// MaxRange returns the engagement range of the station the target was scanned from
+func (t *Target) MaxRange() float64 {
+	if t.maxRange == 0 {
+		return DefaultMaxRange
+	}
+	return t.maxRange
+}
+
+// IsValid checks if the target is within the station's engagement range
 func (t *Target) IsValid() bool {
-	return t.distance <= 100
+	return t.distance <= t.maxRange
 }
 
 // HasAllies returns true if there are allies present at this target
@@ -61,17 +192,46 @@ func (t *Target) HasAllies() bool {
 	return t.Allies != nil && *t.Allies > 0
 }
 
-// IsMech returns true if the enemy type is mech
+// EndangersAllies returns true if striking the target would hit allies,
+// either at the target itself or within the blast radius set by AssessCollateral
+func (t *Target) EndangersAllies() bool {
+	return t.HasAllies() || t.alliesInBlast
+}
+
+// AssessCollateral records on each target whether an allied position lies
+// within radius km of it
+func AssessCollateral(targets []*Target, allied []Position, radius float64) {
+	for _, t := range targets {
+		t.alliesInBlast = AlliesWithin(t.Coordinates, allied, radius)
+	}
+}
+
+// AlliesWithin reports whether any allied position lies within radius km of p
+func AlliesWithin(p Position, allied []Position, radius float64) bool {
+	for _, a := range allied {
+		if p.DistanceTo(a) <= radius {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMech returns true if any enemy group at the target is mech
 func (t *Target) IsMech() bool {
-	return t.Enemies.Type == EnemyTypeMech
+	return t.Enemies.Has(EnemyTypeMech)
+}
+
+// EnemyCount returns the number of enemies at the target across all groups
+func (t *Target) EnemyCount() int {
+	return t.Enemies.Total()
 }
 
 // ScanData represents the complete scan information from probe droids
 type ScanData struct {
 	Protocols []string `json:"protocols"`
 	Scan      []struct {
-		Coordinates Position   `json:"coordinates"`
-		Enemies     EnemyGroup `json:"enemies"`
-		Allies      *int       `json:"allies,omitempty"`
+		Coordinates Position    `json:"coordinates"`
+		Enemies     EnemyGroups `json:"enemies"`
+		Allies      *int        `json:"allies,omitempty"`
 	} `json:"scan"`
 }
@@ -0,0 +1,81 @@
+package target
+
+import (
+	"fmt"
+	"math"
+)
+
+// Zone is a no-fire zone: an area, given as a circle or a polygon, that must
+// never be attacked. Points on the boundary are inside the zone.
+type Zone struct {
+	Name    string     `json:"name,omitempty"`
+	Circle  *Circle    `json:"circle,omitempty"`
+	Polygon []Position `json:"polygon,omitempty"`
+}
+
+// Circle is a circular area
+type Circle struct {
+	Center Position `json:"center"`
+	Radius float64  `json:"radius"`
+}
+
+// Validate checks that the zone has exactly one usable shape
+func (z Zone) Validate() error {
+	switch {
+	case z.Circle != nil && z.Polygon != nil:
+		return fmt.Errorf("%w: both circle and polygon given", ErrInvalidZone)
+	case z.Circle != nil:
+		if z.Circle.Radius <= 0 || math.IsInf(z.Circle.Radius, 0) {
+			return fmt.Errorf("%w: radius must be positive, got %g", ErrInvalidZone, z.Circle.Radius)
+		}
+	case z.Polygon != nil:
+		if len(z.Polygon) < 3 {
+			return fmt.Errorf("%w: polygon needs at least 3 vertices, got %d", ErrInvalidZone, len(z.Polygon))
+		}
+	default:
+		return fmt.Errorf("%w: circle or polygon required", ErrInvalidZone)
+	}
+	return nil
+}
+
+// Contains reports whether p lies inside the zone or on its boundary
+func (z Zone) Contains(p Position) bool {
+	switch {
+	case z.Circle != nil:
+		return p.DistanceTo(z.Circle.Center) <= z.Circle.Radius
+	case len(z.Polygon) >= 3:
+		return polygonContains(z.Polygon, p)
+	default:
+		return false
+	}
+}
+
+// polygonContains tests p against the polygon by ray casting, counting
+// points on an edge as inside
+func polygonContains(vertices []Position, p Position) bool {
+	inside := false
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+
+		if onSegment(a, b, p) {
+			return true
+		}
+
+		// Count edges crossed by a ray from p towards +x
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := float64(a.X) + float64(p.Y-a.Y)*float64(b.X-a.X)/float64(b.Y-a.Y)
+			if float64(p.X) < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// onSegment reports whether p lies on the segment from a to b
+func onSegment(a, b, p Position) bool {
+	cross := (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+	return cross == 0 &&
+		min(a.X, b.X) <= p.X && p.X <= max(a.X, b.X) &&
+		min(a.Y, b.Y) <= p.Y && p.Y <= max(a.Y, b.Y)
+}
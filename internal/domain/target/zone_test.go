@@ -0,0 +1,67 @@
+package target
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestZone_Contains(t *testing.T) {
+	circle := Zone{Circle: &Circle{Center: Position{X: 10, Y: 10}, Radius: 5}}
+	// L-shaped polygon, concave at (5,5)
+	polygon := Zone{Polygon: []Position{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}, {X: 5, Y: 5}, {X: 5, Y: 10}, {X: 0, Y: 10}}}
+
+	tests := []struct {
+		name  string
+		zone  Zone
+		point Position
+		want  bool
+	}{
+		{name: "circle centre", zone: circle, point: Position{X: 10, Y: 10}, want: true},
+		{name: "circle boundary", zone: circle, point: Position{X: 13, Y: 14}, want: true},
+		{name: "outside circle", zone: circle, point: Position{X: 14, Y: 14}, want: false},
+		{name: "polygon interior", zone: polygon, point: Position{X: 2, Y: 8}, want: true},
+		{name: "polygon edge", zone: polygon, point: Position{X: 7, Y: 5}, want: true},
+		{name: "polygon vertex", zone: polygon, point: Position{X: 10, Y: 0}, want: true},
+		{name: "polygon concave notch", zone: polygon, point: Position{X: 8, Y: 8}, want: false},
+		{name: "outside polygon", zone: polygon, point: Position{X: -1, Y: 5}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.zone.Contains(tt.point); got != tt.want {
+				t.Errorf("Zone.Contains(%+v) = %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZone_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		zone    Zone
+		wantErr bool
+	}{
+		{name: "circle", zone: Zone{Circle: &Circle{Radius: 1}}},
+		{name: "triangle", zone: Zone{Polygon: []Position{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}}},
+		{name: "no shape", zone: Zone{Name: "empty"}, wantErr: true},
+		{name: "zero radius", zone: Zone{Circle: &Circle{}}, wantErr: true},
+		{name: "two vertices", zone: Zone{Polygon: []Position{{X: 0, Y: 0}, {X: 1, Y: 0}}}, wantErr: true},
+		{
+			name:    "circle and polygon",
+			zone:    Zone{Circle: &Circle{Radius: 1}, Polygon: []Position{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.zone.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Zone.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidZone) {
+				t.Errorf("Expected ErrInvalidZone, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/protocol"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// Environment variables that override values from the config file
+const (
+	EnvConfigPath      = "BATTLESTATION_CONFIG"
+	EnvListenAddr      = "BATTLESTATION_LISTEN_ADDR"
+	EnvCannons         = "BATTLESTATION_CANNONS"
+	EnvCannonTimeout   = "BATTLESTATION_CANNON_TIMEOUT"
+	EnvShutdownTimeout = "BATTLESTATION_SHUTDOWN_TIMEOUT"
+)
+
+// Duration wraps time.Duration so it can be written as "500ms" in JSON
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON accepts either a duration string or a number of nanoseconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		d.Duration = parsed
+		return nil
+	}
+
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid duration: %s", data)
+	}
+	d.Duration = time.Duration(n)
+	return nil
+}
+
+// MarshalJSON writes the duration in its string form
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// CannonConfig describes a single ion cannon endpoint
+type CannonConfig struct {
+	Generation  int     `json:"generation"`
+	URL         string  `json:"url"`
+	BlastRadius float64 `json:"blast_radius,omitempty"`
+}
+
+// ProtocolConfig defines a custom protocol with an expression over target
+// fields, e.g. `enemies.number >= 5 && !has_allies`
+type ProtocolConfig struct {
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Tier      string   `json:"tier"`
+	Expr      string   `json:"expr"`
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// Definition compiles the protocol into a registry definition
+func (p ProtocolConfig) Definition() (protocol.Definition, error) {
+	tier, err := protocol.ParseTier(p.Tier)
+	if err != nil {
+		return protocol.Definition{}, fmt.Errorf("protocol %s: %w", p.Name, err)
+	}
+
+	return protocol.ExpressionDefinition{
+		Name:      p.Name,
+		Kind:      protocol.ExpressionKind(p.Kind),
+		Tier:      tier,
+		Expr:      p.Expr,
+		Conflicts: p.Conflicts,
+	}.Definition()
+}
+
+// BreakerConfig configures the circuit breaker of every cannon
+type BreakerConfig struct {
+	FailureThreshold int      `json:"failure_threshold"`
+	OpenTimeout      Duration `json:"open_timeout"`
+	SuccessThreshold int      `json:"success_threshold"`
+}
+
+// Config holds the battle station server configuration
+type Config struct {
+	ListenAddr        string           `json:"listen_addr"`
+	ShutdownTimeout   Duration         `json:"shutdown_timeout"`
+	CannonTimeout     Duration         `json:"cannon_timeout"`
+	StatusTimeout     Duration         `json:"status_timeout"`
+	FireTimeout       Duration         `json:"fire_timeout"`
+	RequestTimeout    Duration         `json:"request_timeout"`
+	LeaseTimeout      Duration         `json:"lease_timeout"`
+	MaxAttempts       int              `json:"max_attempts"`
+	TieBreaker        string           `json:"tie_breaker"`
+	Breaker           BreakerConfig    `json:"breaker"`
+	PollInterval      Duration         `json:"poll_interval"`
+	StaleGrace        Duration         `json:"stale_grace"`
+	MaxJobs           int              `json:"max_jobs"`
+	JobTTL            Duration         `json:"job_ttl"`
+	JobWorkers        int              `json:"job_workers"`
+	IdempotencyWindow Duration         `json:"idempotency_window"`
+	IdempotencyKeys   int              `json:"idempotency_keys"`
+	Cannons           []CannonConfig   `json:"cannons"`
+	Protocols         []ProtocolConfig `json:"protocols,omitempty"`
+	NoFireZones       []target.Zone    `json:"no_fire_zones,omitempty"`
+}
+
+// Default returns the configuration used by the docker-compose deployment
+func Default() *Config {
+	return &Config{
+		ListenAddr:      ":8080",
+		ShutdownTimeout: Duration{10 * time.Second},
+		CannonTimeout:   Duration{500 * time.Millisecond},
+		StatusTimeout:   Duration{100 * time.Millisecond},
+		FireTimeout:     Duration{500 * time.Millisecond},
+		RequestTimeout:  Duration{time.Second},
+		LeaseTimeout:    Duration{time.Second},
+		MaxAttempts:     3,
+		TieBreaker:      string(protocol.DefaultTieBreaker),
+		Breaker: BreakerConfig{
+			FailureThreshold: 5,
+			OpenTimeout:      Duration{5 * time.Second},
+			SuccessThreshold: 1,
+		},
+		PollInterval:      Duration{50 * time.Millisecond},
+		StaleGrace:        Duration{time.Second},
+		MaxJobs:           attack.DefaultMaxJobs,
+		JobTTL:            Duration{attack.DefaultJobTTL},
+		JobWorkers:        4,
+		IdempotencyWindow: Duration{10 * time.Minute},
+		IdempotencyKeys:   10000,
+		Cannons: []CannonConfig{
+			{Generation: 1, URL: "http://ion-cannon-1:8080"},
+			{Generation: 2, URL: "http://ion-cannon-2:8080"},
+			{Generation: 3, URL: "http://ion-cannon-3:8080"},
+		},
+	}
+}
+
+// Load builds the configuration from defaults, an optional JSON file and
+// environment variable overrides, in that order. An empty path falls back
+// to the BATTLESTATION_CONFIG environment variable.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv(EnvConfigPath)
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// applyEnv overrides configuration values with those set in the environment
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	if v, ok := lookup(EnvListenAddr); ok {
+		c.ListenAddr = v
+	}
+
+	if v, ok := lookup(EnvCannonTimeout); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvCannonTimeout, err)
+		}
+		c.CannonTimeout = Duration{d}
+	}
+
+	if v, ok := lookup(EnvShutdownTimeout); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvShutdownTimeout, err)
+		}
+		c.ShutdownTimeout = Duration{d}
+	}
+
+	if v, ok := lookup(EnvCannons); ok {
+		cannons, err := parseCannons(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvCannons, err)
+		}
+		c.Cannons = cannons
+	}
+
+	return nil
+}
+
+// parseCannons parses a list of cannons in the form "1=http://a,2=http://b"
+func parseCannons(value string) ([]CannonConfig, error) {
+	var cannons []CannonConfig
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		gen, url, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected generation=url, got %q", entry)
+		}
+
+		generation, err := strconv.Atoi(strings.TrimSpace(gen))
+		if err != nil {
+			return nil, fmt.Errorf("invalid generation %q: %w", gen, err)
+		}
+
+		cannons = append(cannons, CannonConfig{
+			Generation: generation,
+			URL:        strings.TrimSpace(url),
+		})
+	}
+	return cannons, nil
+}
+
+// Validate checks that the configuration can be used to start the server
+func (c *Config) Validate() error {
+	if c.ListenAddr == "" {
+		return fmt.Errorf("listen address is required")
+	}
+
+	if c.CannonTimeout.Duration <= 0 {
+		return fmt.Errorf("cannon timeout must be positive")
+	}
+
+	if c.StatusTimeout.Duration <= 0 || c.FireTimeout.Duration <= 0 || c.RequestTimeout.Duration <= 0 {
+		return fmt.Errorf("status, fire and request timeouts must be positive")
+	}
+
+	if c.LeaseTimeout.Duration <= 0 {
+		return fmt.Errorf("lease timeout must be positive")
+	}
+
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1")
+	}
+
+	if _, err := protocol.ParseTieBreaker(c.TieBreaker); err != nil {
+		return err
+	}
+
+	if c.Breaker.FailureThreshold < 1 || c.Breaker.SuccessThreshold < 1 {
+		return fmt.Errorf("breaker thresholds must be at least 1")
+	}
+
+	if c.Breaker.OpenTimeout.Duration <= 0 {
+		return fmt.Errorf("breaker open timeout must be positive")
+	}
+
+	if c.PollInterval.Duration <= 0 {
+		return fmt.Errorf("poll interval must be positive")
+	}
+
+	if c.StaleGrace.Duration < 0 {
+		return fmt.Errorf("stale grace must not be negative")
+	}
+
+	if c.MaxJobs < 1 || c.JobWorkers < 1 {
+		return fmt.Errorf("max jobs and job workers must be at least 1")
+	}
+
+	if c.JobTTL.Duration <= 0 {
+		return fmt.Errorf("job ttl must be positive")
+	}
+
+	if c.IdempotencyWindow.Duration <= 0 {
+		return fmt.Errorf("idempotency window must be positive")
+	}
+
+	if c.IdempotencyKeys < 1 {
+		return fmt.Errorf("idempotency keys must be at least 1")
+	}
+
+	if c.ShutdownTimeout.Duration <= 0 {
+		return fmt.Errorf("shutdown timeout must be positive")
+	}
+
+	if len(c.Cannons) == 0 {
+		return fmt.Errorf("at least one cannon is required")
+	}
+
+	for i, cannon := range c.Cannons {
+		if cannon.Generation < 1 || cannon.Generation > 3 {
+			return fmt.Errorf("cannon %d: invalid generation %d", i, cannon.Generation)
+		}
+		if cannon.URL == "" {
+			return fmt.Errorf("cannon %d: url is required", i)
+		}
+		if cannon.BlastRadius < 0 {
+			return fmt.Errorf("cannon %d: blast radius must not be negative", i)
+		}
+	}
+
+	for i, zone := range c.NoFireZones {
+		if err := zone.Validate(); err != nil {
+			return fmt.Errorf("no-fire zone %d: %w", i, err)
+		}
+	}
+
+	return c.validateProtocols()
+}
+
+// validateProtocols checks that custom protocols are named, compile, do not
+// shadow a registered protocol and only conflict with protocols that exist
+func (c *Config) validateProtocols() error {
+	registry := protocol.DefaultRegistry()
+
+	names := make(map[string]bool, len(c.Protocols))
+	for i, p := range c.Protocols {
+		if p.Name == "" {
+			return fmt.Errorf("protocol %d: name is required", i)
+		}
+		if _, exists := registry.Lookup(p.Name); exists || names[p.Name] {
+			return fmt.Errorf("protocol %s: %w", p.Name, protocol.ErrDuplicateProtocol)
+		}
+		names[p.Name] = true
+	}
+
+	for _, p := range c.Protocols {
+		if _, err := p.Definition(); err != nil {
+			return err
+		}
+		for _, other := range p.Conflicts {
+			if _, exists := registry.Lookup(other); !exists && !names[other] {
+				return fmt.Errorf("protocol %s: conflicts with unknown protocol %s", p.Name, other)
+			}
+		}
+	}
+
+	return nil
+}
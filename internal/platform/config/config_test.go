@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		want    func() *Config
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: Default,
+		},
+		{
+			name: "config file",
+			file: `{
+				"listen_addr": ":9000",
+				"cannon_timeout": "250ms",
+				"cannons": [{"generation": 2, "url": "http://localhost:8082"}]
+			}`,
+			want: func() *Config {
+				cfg := Default()
+				cfg.ListenAddr = ":9000"
+				cfg.CannonTimeout = Duration{250 * time.Millisecond}
+				cfg.Cannons = []CannonConfig{{Generation: 2, URL: "http://localhost:8082"}}
+				return cfg
+			},
+		},
+		{
+			name: "environment overrides file",
+			file: `{"listen_addr": ":9000"}`,
+			env: map[string]string{
+				EnvListenAddr:      ":7000",
+				EnvShutdownTimeout: "2s",
+				EnvCannons:         "1=http://localhost:8081, 3=http://localhost:8083",
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.ListenAddr = ":7000"
+				cfg.ShutdownTimeout = Duration{2 * time.Second}
+				cfg.Cannons = []CannonConfig{
+					{Generation: 1, URL: "http://localhost:8081"},
+					{Generation: 3, URL: "http://localhost:8083"},
+				}
+				return cfg
+			},
+		},
+		{
+			name:    "invalid cannon list",
+			env:     map[string]string{EnvCannons: "http://localhost:8081"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid generation",
+			file:    `{"cannons": [{"generation": 4, "url": "http://localhost:8084"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			file:    `{"cannon_timeout": "soon"}`,
+			wantErr: true,
+		},
+		{
+			name: "custom protocols",
+			file: `{"protocols": [
+				{"name": "big-groups", "kind": "filter", "tier": "validation", "expr": "enemies.number >= 5 && !has_allies"},
+				{"name": "near", "kind": "prefer", "tier": "position", "expr": "distance < 40", "conflicts": ["furthest-enemies", "big-groups"]}
+			]}`,
+			want: func() *Config {
+				cfg := Default()
+				cfg.Protocols = []ProtocolConfig{
+					{Name: "big-groups", Kind: "filter", Tier: "validation", Expr: "enemies.number >= 5 && !has_allies"},
+					{Name: "near", Kind: "prefer", Tier: "position", Expr: "distance < 40", Conflicts: []string{"furthest-enemies", "big-groups"}},
+				}
+				return cfg
+			},
+		},
+		{
+			name:    "invalid protocol expression",
+			file:    `{"protocols": [{"name": "near", "kind": "filter", "tier": "position", "expr": "distance <"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid protocol tier",
+			file:    `{"protocols": [{"name": "near", "kind": "filter", "tier": "strategic", "expr": "distance < 40"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "protocol shadows built-in",
+			file:    `{"protocols": [{"name": "avoid-mech", "kind": "filter", "tier": "validation", "expr": "!is_mech"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unnamed protocol",
+			file:    `{"protocols": [{"name": "", "kind": "filter", "tier": "validation", "expr": "distance < 10"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "protocol conflicts with unknown protocol",
+			file:    `{"protocols": [{"name": "near", "kind": "filter", "tier": "position", "expr": "distance < 40", "conflicts": ["far"]}]}`,
+			wantErr: true,
+		},
+		{
+			name: "no-fire zones",
+			file: `{"no_fire_zones": [{"name": "base", "circle": {"center": {"x": 0, "y": 0}, "radius": 10}}]}`,
+			want: func() *Config {
+				cfg := Default()
+				cfg.NoFireZones = []target.Zone{{Name: "base", Circle: &target.Circle{Radius: 10}}}
+				return cfg
+			},
+		},
+		{
+			name:    "invalid no-fire zone",
+			file:    `{"no_fire_zones": [{"polygon": [{"x": 0, "y": 0}, {"x": 1, "y": 1}]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "negative blast radius",
+			file:    `{"cannons": [{"generation": 1, "url": "http://localhost:8081", "blast_radius": -1}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "no job workers",
+			file:    `{"job_workers": 0}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero idempotency window",
+			file:    `{"idempotency_window": "0s"}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero idempotency keys",
+			file:    `{"idempotency_keys": 0}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid tie breaker",
+			file:    `{"tie_breaker": "coin-flip"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var path string
+			if tt.file != "" {
+				path = filepath.Join(t.TempDir(), "config.json")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("Failed to write config file: %v", err)
+				}
+			}
+
+			got, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want()) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want())
+			}
+		})
+	}
+}
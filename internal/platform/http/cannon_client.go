@@ -19,18 +19,29 @@ type CannonClient struct {
 	timeout time.Duration
 }
 
-// NewCannonClient creates a new HTTP client for ion cannons
+// NewCannonClient creates a new HTTP client for ion cannons. Calls are bound
+// by the deadline of their context; timeout only applies to calls whose
+// context has none.
 func NewCannonClient(timeout time.Duration) *CannonClient {
 	return &CannonClient{
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		client:  &http.Client{},
 		timeout: timeout,
 	}
 }
 
+// withTimeout applies the default timeout to contexts without a deadline
+func (c *CannonClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
 // GetStatus retrieves the current status of an ion cannon
 func (c *CannonClient) GetStatus(ctx context.Context, baseURL string) (*cannon.Status, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	// Create request with context
 	statusURL, err := url.JoinPath(baseURL, "status")
 	if err != nil {
@@ -79,6 +90,9 @@ func (c *CannonClient) GetStatus(ctx context.Context, baseURL string) (*cannon.S
 
 // Fire sends a fire request to an ion cannon
 func (c *CannonClient) Fire(ctx context.Context, baseURL string, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	// Marshal request body
 	body, err := json.Marshal(req)
 	if err != nil {
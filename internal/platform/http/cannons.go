@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+)
+
+// FleetStatus reports the state of the managed ion cannons
+type FleetStatus interface {
+	Reports(ctx context.Context) []cannon.Report
+	Report(ctx context.Context, generation cannon.Generation) (*cannon.Report, error)
+}
+
+// FleetResponse is the response body of GET /cannons
+type FleetResponse struct {
+	Cannons []cannon.Report `json:"cannons"`
+}
+
+// handleCannons reports the state of every cannon
+func (h *Handler) handleCannons(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	resp := FleetResponse{Cannons: h.fleet.Reports(r.Context())}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("Failed to write response",
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// handleCannon reports the state of a single cannon
+func (h *Handler) handleCannon(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	generation, err := strconv.Atoi(r.PathValue("generation"))
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid generation %q", errBadRequest, r.PathValue("generation")))
+		return
+	}
+
+	report, err := h.fleet.Report(r.Context(), cannon.Generation(generation))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.logger.Error("Failed to write response",
+			slog.String("error", err.Error()),
+		)
+	}
+}
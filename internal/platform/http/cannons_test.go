@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+)
+
+// MockFleetStatus implements FleetStatus for testing
+type MockFleetStatus struct {
+	reports []cannon.Report
+}
+
+func (m *MockFleetStatus) Reports(ctx context.Context) []cannon.Report {
+	return m.reports
+}
+
+func (m *MockFleetStatus) Report(ctx context.Context, generation cannon.Generation) (*cannon.Report, error) {
+	for _, r := range m.reports {
+		if r.Generation == int(generation) {
+			return &r, nil
+		}
+	}
+	return nil, fmt.Errorf("generation %d: %w", generation, cannon.ErrCannonNotFound)
+}
+
+func TestHandler_HandleCannons(t *testing.T) {
+	fleet := &MockFleetStatus{
+		reports: []cannon.Report{
+			{Generation: 1, Available: false, ReadyInSeconds: 2.5, BreakerState: "closed"},
+			{Generation: 2, Available: true, BreakerState: "closed"},
+		},
+	}
+	handler := NewHandler(nil, nil, WithFleetStatus(fleet))
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("fleet", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/cannons")
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Handler returned wrong status code: got %v want %v", resp.StatusCode, http.StatusOK)
+		}
+
+		var got FleetResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(got.Cannons) != 2 || got.Cannons[0].ReadyInSeconds != 2.5 {
+			t.Errorf("Handler returned unexpected fleet: %+v", got)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "known generation", path: "/cannons/2", wantStatus: http.StatusOK},
+		{name: "unknown generation", path: "/cannons/3", wantStatus: http.StatusNotFound},
+		{name: "invalid generation", path: "/cannons/first", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Handler returned wrong status code: got %v want %v", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package http
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,29 +10,67 @@ import (
 	"time"
 
 	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/protocol"
 	"github.com/aitoroses/battlestation-codetest/internal/platform/metrics"
 )
 
 // Handler handles HTTP requests for the battle station
 type Handler struct {
 	coordinator *attack.Coordinator
+	fleet       FleetStatus
+	jobs        AttackJobs
+	idempotency *idempotencyStore
 	logger      *slog.Logger
 }
 
+// HandlerOption configures optional Handler behaviour
+type HandlerOption func(*Handler)
+
+// WithFleetStatus enables the cannon status endpoints
+func WithFleetStatus(fleet FleetStatus) HandlerOption {
+	return func(h *Handler) {
+		h.fleet = fleet
+	}
+}
+
+// WithAttackJobs enables the asynchronous attack endpoints
+func WithAttackJobs(jobs AttackJobs) HandlerOption {
+	return func(h *Handler) {
+		h.jobs = jobs
+	}
+}
+
 // NewHandler creates a new HTTP handler
-func NewHandler(coordinator *attack.Coordinator, logger *slog.Logger) *Handler {
+func NewHandler(coordinator *attack.Coordinator, logger *slog.Logger, opts ...HandlerOption) *Handler {
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &Handler{
+	h := &Handler{
 		coordinator: coordinator,
+		idempotency: newIdempotencyStore(DefaultIdempotencyWindow, DefaultIdempotencyKeys),
 		logger:      logger,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // RegisterRoutes registers all HTTP routes
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("POST /attack", h.handleAttack)
+	mux.HandleFunc("POST /attack", withRequestID(h.withIdempotency(h.handleAttack)))
+	mux.HandleFunc("POST /attack/plan", withRequestID(h.handlePlan))
+	mux.HandleFunc("POST /attack/salvo", withRequestID(h.withIdempotency(h.handleSalvo)))
+
+	if h.fleet != nil {
+		mux.HandleFunc("GET /cannons", withRequestID(h.handleCannons))
+		mux.HandleFunc("GET /cannons/{generation}", withRequestID(h.handleCannon))
+	}
+
+	if h.jobs != nil {
+		mux.HandleFunc("POST /attacks", withRequestID(h.withIdempotency(h.handleSubmitJob)))
+		mux.HandleFunc("GET /attacks/{id}", withRequestID(h.handleGetJob))
+	}
 }
 
 // handleAttack processes attack requests
@@ -44,23 +81,8 @@ func (h *Handler) handleAttack(w http.ResponseWriter, r *http.Request) {
 	// Start request timing
 	start := time.Now()
 
-	// Read request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		h.writeError(w, fmt.Errorf("failed to read request body: %w", err), http.StatusBadRequest)
-		return
-	}
-
-	// Parse request
-	var req attack.Request
-	if err := json.Unmarshal(body, &req); err != nil {
-		h.writeError(w, fmt.Errorf("failed to parse request: %w", err), http.StatusBadRequest)
-		return
-	}
-
-	// Validate request
-	if err := attack.ValidateRequest(&req); err != nil {
-		h.writeError(w, fmt.Errorf("invalid request: %w", err), http.StatusBadRequest)
+	req, ok := h.decodeRequest(w, r)
+	if !ok {
 		return
 	}
 
@@ -68,31 +90,23 @@ func (h *Handler) handleAttack(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Process attack
-	resp, err := h.coordinator.ProcessAttack(ctx, &req)
+	resp, err := h.coordinator.ProcessAttack(ctx, req)
 	duration := time.Since(start)
 
 	// Record metrics
-	for _, protocol := range req.Protocols {
-		metrics.RecordRequestDuration(protocol, duration.Seconds())
-		if err != nil {
-			metrics.RecordRequestComplete(protocol, "error")
-		} else {
-			metrics.RecordRequestComplete(protocol, "success")
-		}
-	}
+	recordRequestMetrics(req, duration, err)
 
 	// Log request details
 	h.logger.Info("Attack request processed",
 		slog.Duration("duration", duration),
 		slog.Any("protocols", req.Protocols),
 		slog.Int("targets", len(req.Scan)),
+		slog.String("request_id", RequestIDFromContext(r.Context())),
 		slog.Any("error", err),
 	)
 
 	if err != nil {
-		// Determine appropriate status code based on error
-		statusCode := h.determineStatusCode(err)
-		h.writeError(w, err, statusCode)
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -104,45 +118,126 @@ func (h *Handler) handleAttack(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// writeError writes an error response in JSON format
-func (h *Handler) writeError(w http.ResponseWriter, err error, statusCode int) {
-	w.WriteHeader(statusCode)
-	response := struct {
-		Error string `json:"error"`
-	}{
-		Error: err.Error(),
+// handleSalvo fires every available cannon at distinct targets
+func (h *Handler) handleSalvo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	start := time.Now()
+
+	req, ok := h.decodeRequest(w, r)
+	if !ok {
+		return
 	}
 
-	h.logger.Error("Request error",
-		slog.String("error", err.Error()),
-		slog.Int("status_code", statusCode),
+	resp, err := h.coordinator.ProcessSalvo(r.Context(), req)
+	duration := time.Since(start)
+
+	recordRequestMetrics(req, duration, err)
+
+	shots := 0
+	if resp != nil {
+		shots = len(resp.Shots)
+	}
+	h.logger.Info("Salvo request processed",
+		slog.Duration("duration", duration),
+		slog.Any("protocols", req.Protocols),
+		slog.Int("targets", len(req.Scan)),
+		slog.Int("shots", shots),
+		slog.String("request_id", RequestIDFromContext(r.Context())),
+		slog.Any("error", err),
 	)
 
-	metrics.RecordError("http", err.Error())
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Error("Failed to write error response",
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("Failed to write response",
 			slog.String("error", err.Error()),
 		)
 	}
 }
 
-// determineStatusCode maps errors to appropriate HTTP status codes
-func (h *Handler) determineStatusCode(err error) int {
-	switch {
-	case errors.Is(err, context.DeadlineExceeded):
-		return http.StatusGatewayTimeout
-	case errors.Is(err, context.Canceled):
-		return http.StatusServiceUnavailable
-	default:
-		// Check error string for specific cases
-		switch err.Error() {
-		case "no cannons available":
-			return http.StatusServiceUnavailable
-		case "no valid targets in range":
-			return http.StatusBadRequest
-		default:
-			return http.StatusInternalServerError
-		}
+// RequestMetrics records attack jobs in the request metrics, so attacks run
+// in the background are counted like those answered synchronously
+type RequestMetrics struct{}
+
+// JobFinished records the duration and outcome of a finished attack job
+func (RequestMetrics) JobFinished(req *attack.Request, duration time.Duration, err error) {
+	recordRequestMetrics(req, duration, err)
+}
+
+// recordRequestMetrics records duration and outcome of an attack request
+func recordRequestMetrics(req *attack.Request, duration time.Duration, err error) {
+	if errors.Is(err, attack.ErrRequestTimeout) {
+		metrics.RecordPhaseTimeout("request")
+	}
+
+	outcome, statusCode := "success", http.StatusOK
+	if err != nil {
+		pt := classifyError(err)
+		outcome, statusCode = pt.code, pt.status
 	}
+
+	metrics.RecordRequest(protocol.Combination(req.Protocols), outcome, statusCode, duration.Seconds())
+}
+
+// handlePlan runs target and cannon selection for a request without firing
+func (h *Handler) handlePlan(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	start := time.Now()
+
+	req, ok := h.decodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.coordinator.PlanAttack(r.Context(), req)
+
+	h.logger.Info("Attack plan processed",
+		slog.Duration("duration", time.Since(start)),
+		slog.Any("protocols", req.Protocols),
+		slog.Int("targets", len(req.Scan)),
+		slog.String("request_id", RequestIDFromContext(r.Context())),
+		slog.Any("error", err),
+	)
+
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(plan); err != nil {
+		h.logger.Error("Failed to write response",
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// decodeRequest reads, parses and validates an attack request.
+// It writes the error response and returns false when the request is unusable.
+func (h *Handler) decodeRequest(w http.ResponseWriter, r *http.Request) (*attack.Request, bool) {
+	// Read request body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: failed to read request body: %w", errBadRequest, err))
+		return nil, false
+	}
+
+	// Parse request
+	var req attack.Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: failed to parse request: %w", errBadRequest, err))
+		return nil, false
+	}
+
+	// Validate request
+	if err := attack.ValidateRequest(&req); err != nil {
+		h.writeError(w, r, fmt.Errorf("invalid request: %w", err))
+		return nil, false
+	}
+
+	return &req, true
 }
@@ -4,12 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
 	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+	"github.com/aitoroses/battlestation-codetest/internal/platform/metrics"
 )
 
 // MockCannonManager implements attack.CannonManager for testing
@@ -24,7 +32,24 @@ func (m *MockCannonManager) GetBestAvailable(ctx context.Context) (*cannon.IonCa
 	return m.bestCannon, m.bestErr
 }
 
-func (m *MockCannonManager) Fire(ctx context.Context, c *cannon.IonCannon, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+func (m *MockCannonManager) Reserve(ctx context.Context, exclude ...*cannon.IonCannon) (*cannon.Reservation, error) {
+	if m.bestErr != nil {
+		return nil, m.bestErr
+	}
+	return &cannon.Reservation{Cannon: m.bestCannon}, nil
+}
+
+func (m *MockCannonManager) ReserveN(ctx context.Context, n int) ([]*cannon.Reservation, error) {
+	r, err := m.Reserve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []*cannon.Reservation{r}, nil
+}
+
+func (m *MockCannonManager) Release(r *cannon.Reservation) {}
+
+func (m *MockCannonManager) Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error) {
 	return m.fireResp, m.fireErr
 }
 
@@ -173,6 +198,311 @@ func TestHandler_HandleAttack(t *testing.T) {
 	}
 }
 
+func TestHandler_HandleAttack_RequestMetrics(t *testing.T) {
+	mockManager := &MockCannonManager{
+		bestCannon: cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+		fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+	}
+	handler := NewHandler(attack.NewCoordinator(mockManager), nil)
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	counter := metrics.RequestTotal.WithLabelValues("avoid-crossfire+avoid-mech+closest-enemies", "success", "200")
+	before := testutil.ToFloat64(counter)
+
+	body := `{
+		"protocols": ["closest-enemies", "avoid-mech", "avoid-crossfire"],
+		"scan": [{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 10}}]
+	}`
+	resp, err := http.Post(server.URL+"/attack", "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("Expected the request to be counted once, got %v", got)
+	}
+}
+
+func TestHandler_HandleAttack_Errors(t *testing.T) {
+	validBody := `{
+		"protocols": ["closest-enemies"],
+		"scan": [{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 10}}]
+	}`
+
+	tests := []struct {
+		name        string
+		requestBody string
+		manager     *MockCannonManager
+		wantStatus  int
+		wantCode    string
+	}{
+		{
+			name:        "malformed body",
+			requestBody: `{"protocols": [`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "bad_request",
+		},
+		{
+			name: "invalid scan point",
+			requestBody: `{
+				"protocols": ["closest-enemies"],
+				"scan": [{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 0}}]
+			}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_request",
+		},
+		{
+			name: "incompatible protocols",
+			requestBody: `{
+				"protocols": ["closest-enemies", "furthest-enemies"],
+				"scan": [{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 10}}]
+			}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "incompatible_protocols",
+		},
+		{
+			name: "no targets in range",
+			requestBody: `{
+				"protocols": ["closest-enemies"],
+				"scan": [{"coordinates": {"x": 0, "y": 140}, "enemies": {"type": "soldier", "number": 10}}]
+			}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "no_valid_targets",
+		},
+		{
+			name:        "no cannon available",
+			requestBody: validBody,
+			manager:     &MockCannonManager{bestErr: cannon.ErrNoCannonAvailable},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantCode:    "no_cannon_available",
+		},
+		{
+			name:        "cannon fire failed",
+			requestBody: validBody,
+			manager: &MockCannonManager{
+				bestCannon: cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+				fireErr:    errors.New("connection refused"),
+			},
+			wantStatus: http.StatusBadGateway,
+			wantCode:   "fire_failed",
+		},
+		{
+			name:        "cannon timed out",
+			requestBody: validBody,
+			manager: &MockCannonManager{
+				bestCannon: cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+				fireErr:    fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			},
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   "timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := tt.manager
+			if manager == nil {
+				manager = &MockCannonManager{}
+			}
+			handler := NewHandler(attack.NewCoordinator(manager), nil)
+
+			mux := http.NewServeMux()
+			handler.RegisterRoutes(mux)
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+"/attack", "application/json", bytes.NewBufferString(tt.requestBody))
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Handler returned wrong status code: got %v want %v", resp.StatusCode, tt.wantStatus)
+			}
+
+			if ct := resp.Header.Get("Content-Type"); ct != ProblemContentType {
+				t.Errorf("Handler returned wrong content type: got %q want %q", ct, ProblemContentType)
+			}
+
+			var got Problem
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if got.Code != tt.wantCode || got.Status != tt.wantStatus {
+				t.Errorf("Handler returned wrong problem: got %q/%d want %q/%d (%s)", got.Code, got.Status, tt.wantCode, tt.wantStatus, got.Detail)
+			}
+			if got.RequestID == "" || got.RequestID != resp.Header.Get(RequestIDHeader) {
+				t.Errorf("Expected problem request ID %q to match response header %q", got.RequestID, resp.Header.Get(RequestIDHeader))
+			}
+		})
+	}
+}
+
+func TestHandler_HandleAttack_InvalidParams(t *testing.T) {
+	handler := NewHandler(attack.NewCoordinator(&MockCannonManager{}), nil)
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		scan string
+		want []string
+	}{
+		{
+			name: "enemy group list",
+			scan: `[
+				{"coordinates": {"x": 0, "y": 40}, "enemies": [{"type": "soldier", "number": 10}]},
+				{"coordinates": {"x": 0, "y": 50}, "enemies": [{"type": "soldier", "number": 5}, {"type": "tank", "number": 0}]},
+				{"coordinates": {"x": 0, "y": 60}, "allies": -2, "enemies": [{"type": "mech", "number": 1}]}
+			]`,
+			want: []string{"scan[1].enemies[1].type", "scan[1].enemies[1].number", "scan[2].allies"},
+		},
+		{
+			name: "single enemy group object",
+			scan: `[
+				{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 10}},
+				{"coordinates": {"x": 0, "y": 50}, "enemies": {"type": "tank", "number": 0}},
+				{"coordinates": {"x": 0, "y": 60}, "allies": -2, "enemies": {"type": "mech", "number": 1}}
+			]`,
+			want: []string{"scan[1].enemies.type", "scan[1].enemies.number", "scan[2].allies"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"protocols": ["closest-enemies"], "scan": ` + tt.scan + `}`
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/attack", bytes.NewBufferString(body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			req.Header.Set(RequestIDHeader, "req-42")
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var got Problem
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if got.RequestID != "req-42" || got.Instance != "/attack" || got.Code != "invalid_request" {
+				t.Errorf("Handler returned unexpected problem: %+v", got)
+			}
+
+			var fields []string
+			for _, p := range got.InvalidParams {
+				fields = append(fields, p.Name)
+			}
+			// Field names follow the shape the client sent
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("Handler returned invalid params %v, want %v", fields, tt.want)
+			}
+		})
+	}
+}
+
+// hangingHTTPClient never answers a status check before its deadline
+type hangingHTTPClient struct{}
+
+func (hangingHTTPClient) GetStatus(ctx context.Context, baseURL string) (*cannon.Status, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (hangingHTTPClient) Fire(ctx context.Context, baseURL string, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+	return nil, errors.New("fire must not be reached")
+}
+
+func TestHandler_StatusTimeout(t *testing.T) {
+	var cannons []*cannon.IonCannon
+	for _, gen := range []cannon.Generation{cannon.Generation1, cannon.Generation2} {
+		cannons = append(cannons, cannon.NewIonCannon(gen, "http://cannon", hangingHTTPClient{},
+			cannon.WithStatusTimeout(10*time.Millisecond)))
+	}
+	handler := NewHandler(attack.NewCoordinator(cannon.NewManager(cannons)), nil)
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	body := `{
+		"protocols": ["closest-enemies"],
+		"scan": [{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 10}}]
+	}`
+
+	// Every status check running out of its deadline is a timeout, not a lack of cannons
+	for _, path := range []string{"/attack", "/attack/plan"} {
+		t.Run(path, func(t *testing.T) {
+			resp, err := http.Post(server.URL+path, "application/json", bytes.NewBufferString(body))
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var got Problem
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.StatusCode != http.StatusGatewayTimeout || got.Code != "timeout" {
+				t.Errorf("Got %d %s (%s), want %d timeout", resp.StatusCode, got.Code, got.Detail, http.StatusGatewayTimeout)
+			}
+		})
+	}
+}
+
+func TestHandler_HandlePlan(t *testing.T) {
+	mockManager := &MockCannonManager{
+		bestCannon: cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+		fireErr:    errors.New("plan must not fire"),
+	}
+	handler := NewHandler(attack.NewCoordinator(mockManager), nil)
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	body := `{
+		"protocols": ["prioritize-mech"],
+		"scan": [
+			{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 10}},
+			{"coordinates": {"x": 0, "y": 80}, "enemies": {"type": "mech", "number": 1}}
+		]
+	}`
+
+	resp, err := http.Post(server.URL+"/attack/plan", "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code: got %v want %v", resp.StatusCode, http.StatusOK)
+	}
+
+	var got attack.Plan
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if got.Target != (target.Position{X: 0, Y: 80}) || got.Generation != 1 {
+		t.Errorf("Handler returned unexpected plan: %+v", got)
+	}
+}
+
 func TestHandler_RegisterRoutes(t *testing.T) {
 	handler := NewHandler(nil, nil)
 	mux := http.NewServeMux()
@@ -0,0 +1,238 @@
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader lets clients retry a request without it taking effect twice
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed for a duplicate key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// DefaultIdempotencyWindow is how long a response is replayed for its key by default
+const DefaultIdempotencyWindow = 10 * time.Minute
+
+// DefaultIdempotencyKeys is how many idempotency keys are remembered by default
+const DefaultIdempotencyKeys = 10000
+
+// maxIdempotencyKeyLength bounds client-supplied idempotency keys
+const maxIdempotencyKeyLength = 255
+
+var (
+	// errIdempotencyKeyReused marks a key sent again with a different request body
+	errIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+	// errTooManyIdempotencyKeys is returned when every remembered key belongs
+	// to a request still running
+	errTooManyIdempotencyKeys = errors.New("too many idempotent requests in flight")
+)
+
+// WithIdempotencyWindow sets how long responses are replayed for a repeated
+// Idempotency-Key
+func WithIdempotencyWindow(d time.Duration) HandlerOption {
+	return func(h *Handler) {
+		if d > 0 {
+			h.idempotency.window = d
+		}
+	}
+}
+
+// WithIdempotencyKeys sets how many idempotency keys are remembered. Once
+// full, the completed response closest to expiring is forgotten to make room.
+func WithIdempotencyKeys(n int) HandlerOption {
+	return func(h *Handler) {
+		if n > 0 {
+			h.idempotency.capacity = n
+		}
+	}
+}
+
+// idempotentResponse is the response of the first request sent with a key.
+// done is closed once it has been recorded.
+type idempotentResponse struct {
+	key         string
+	fingerprint [sha256.Size]byte
+	done        chan struct{}
+	expiresAt   time.Time
+
+	status int
+	header http.Header
+	body   []byte
+}
+
+// idempotencyStore remembers responses by idempotency key for up to
+// capacity keys. Responses are kept for window after they complete; in-flight
+// requests never expire.
+type idempotencyStore struct {
+	mu        sync.Mutex
+	responses map[string]*idempotentResponse
+	// expiring holds completed responses in expiry order, earliest first
+	expiring *list.List
+	window   time.Duration
+	capacity int
+	now      func() time.Time
+}
+
+func newIdempotencyStore(window time.Duration, capacity int) *idempotencyStore {
+	if window <= 0 {
+		window = DefaultIdempotencyWindow
+	}
+	if capacity <= 0 {
+		capacity = DefaultIdempotencyKeys
+	}
+	return &idempotencyStore{
+		responses: make(map[string]*idempotentResponse),
+		expiring:  list.New(),
+		window:    window,
+		capacity:  capacity,
+		now:       time.Now,
+	}
+}
+
+// begin returns the response recorded for key and whether the caller is the
+// first to send it, in which case it must call finish. It fails with
+// errTooManyIdempotencyKeys when the store is full of in-flight requests.
+func (s *idempotencyStore) begin(key string, fingerprint [sha256.Size]byte) (*idempotentResponse, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+
+	if resp, ok := s.responses[key]; ok {
+		return resp, false, nil
+	}
+
+	if len(s.responses) >= s.capacity {
+		oldest := s.expiring.Front()
+		if oldest == nil {
+			return nil, false, errTooManyIdempotencyKeys
+		}
+		s.evict(oldest)
+	}
+
+	resp := &idempotentResponse{key: key, fingerprint: fingerprint, done: make(chan struct{})}
+	s.responses[key] = resp
+	return resp, true, nil
+}
+
+// evictExpired forgets the responses whose window has passed
+func (s *idempotencyStore) evictExpired() {
+	now := s.now()
+	for e := s.expiring.Front(); e != nil; e = s.expiring.Front() {
+		if !now.After(e.Value.(*idempotentResponse).expiresAt) {
+			return
+		}
+		s.evict(e)
+	}
+}
+
+// evict forgets the completed response held by e
+func (s *idempotencyStore) evict(e *list.Element) {
+	resp := s.expiring.Remove(e).(*idempotentResponse)
+	delete(s.responses, resp.key)
+}
+
+// finish records the response and wakes up requests waiting on it
+func (s *idempotencyStore) finish(resp *idempotentResponse, rec *recordingWriter) {
+	header := rec.Header().Clone()
+	header.Del(RequestIDHeader)
+
+	s.mu.Lock()
+	resp.status = rec.status
+	resp.header = header
+	resp.body = rec.body.Bytes()
+	// Every response is kept for the same window, so appending keeps the
+	// list in expiry order
+	resp.expiresAt = s.now().Add(s.window)
+	s.expiring.PushBack(resp)
+	s.mu.Unlock()
+
+	close(resp.done)
+}
+
+// replay writes the recorded response
+func (r *idempotentResponse) replay(w http.ResponseWriter) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(r.status)
+	_, _ = w.Write(r.body)
+}
+
+// recordingWriter passes a response through while keeping a copy of it
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// withIdempotency makes requests carrying an Idempotency-Key take effect
+// once. The first response for a key, success or failure, is replayed to
+// repeats within the window, and repeats arriving while the first request is
+// still running wait for it instead of running again. The first request
+// keeps running if its client goes away, so a retry gets its real outcome.
+func (h *Handler) withIdempotency(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			h.writeError(w, r, fmt.Errorf("%w: %s longer than %d characters", errBadRequest, IdempotencyKeyHeader, maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.writeError(w, r, fmt.Errorf("%w: failed to read request body: %w", errBadRequest, err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := sha256.Sum256(body)
+		resp, first, err := h.idempotency.begin(r.Method+" "+r.URL.Path+" "+key, fingerprint)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if !first {
+			if resp.fingerprint != fingerprint {
+				h.writeError(w, r, fmt.Errorf("%w: %s %q", errIdempotencyKeyReused, IdempotencyKeyHeader, key))
+				return
+			}
+
+			select {
+			case <-resp.done:
+				resp.replay(w)
+			case <-r.Context().Done():
+				h.writeError(w, r, r.Context().Err())
+			}
+			return
+		}
+
+		rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+		defer h.idempotency.finish(resp, rec)
+		next(rec, r.WithContext(context.WithoutCancel(r.Context())))
+	}
+}
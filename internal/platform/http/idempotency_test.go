@@ -0,0 +1,243 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+)
+
+// countingCannonManager counts fires and holds each one until release is closed
+type countingCannonManager struct {
+	MockCannonManager
+	fires   atomic.Int32
+	firing  chan struct{}
+	release chan struct{}
+}
+
+func (m *countingCannonManager) Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+	m.fires.Add(1)
+	if m.firing != nil {
+		m.firing <- struct{}{}
+		<-m.release
+	}
+	return m.fireResp, m.fireErr
+}
+
+const idempotentAttackBody = `{
+	"protocols": ["closest-enemies"],
+	"scan": [{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 10}}]
+}`
+
+// postAttack sends an attack with the given idempotency key and returns the
+// status, body and whether the response was replayed
+func postAttack(t *testing.T, url, key, body string) (int, string, bool) {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodPost, url+"/attack", bytes.NewBufferString(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("Failed to send request: %v", err)
+		return 0, "", false
+	}
+	defer resp.Body.Close()
+
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b), resp.Header.Get(IdempotentReplayedHeader) == "true"
+}
+
+func newIdempotencyServer(manager attack.CannonManager) *httptest.Server {
+	mux := http.NewServeMux()
+	NewHandler(attack.NewCoordinator(manager), nil).RegisterRoutes(mux)
+	return httptest.NewServer(mux)
+}
+
+func TestHandler_Idempotency(t *testing.T) {
+	tests := []struct {
+		name       string
+		fireErr    error
+		keys       []string
+		wantStatus int
+		wantReplay bool
+	}{
+		{name: "success replayed", keys: []string{"a", "a"}, wantStatus: http.StatusOK, wantReplay: true},
+		{name: "failure replayed", fireErr: cannon.ErrCannonNotReady, keys: []string{"a", "a"}, wantStatus: http.StatusBadGateway, wantReplay: true},
+		{name: "distinct keys", keys: []string{"a", "b"}, wantStatus: http.StatusOK},
+		{name: "no key", keys: []string{"", ""}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &countingCannonManager{
+				MockCannonManager: MockCannonManager{
+					bestCannon: &cannon.IonCannon{},
+					fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+					fireErr:    tt.fireErr,
+				},
+			}
+			server := newIdempotencyServer(manager)
+			defer server.Close()
+
+			_, first, _ := postAttack(t, server.URL, tt.keys[0], idempotentAttackBody)
+			fires := manager.fires.Load()
+			status, second, replayed := postAttack(t, server.URL, tt.keys[1], idempotentAttackBody)
+
+			if status != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", status, tt.wantStatus)
+			}
+			if refired := manager.fires.Load() > fires; refired == tt.wantReplay {
+				t.Errorf("Second request fired = %v, want %v", refired, !tt.wantReplay)
+			}
+			if replayed != tt.wantReplay || (tt.wantReplay && first != second) {
+				t.Errorf("Replayed = %v with body %s, want replay %v of %s", replayed, second, tt.wantReplay, first)
+			}
+		})
+	}
+}
+
+func TestHandler_Idempotency_KeyReused(t *testing.T) {
+	manager := &countingCannonManager{
+		MockCannonManager: MockCannonManager{
+			bestCannon: &cannon.IonCannon{},
+			fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+		},
+	}
+	server := newIdempotencyServer(manager)
+	defer server.Close()
+
+	postAttack(t, server.URL, "a", idempotentAttackBody)
+	status, _, _ := postAttack(t, server.URL, "a", `{"protocols": ["furthest-enemies"], "scan": []}`)
+
+	if status != http.StatusUnprocessableEntity {
+		t.Errorf("Status = %d, want %d", status, http.StatusUnprocessableEntity)
+	}
+	if got := manager.fires.Load(); got != 1 {
+		t.Errorf("Cannon fired %d times, want 1", got)
+	}
+}
+
+func TestHandler_Idempotency_Concurrent(t *testing.T) {
+	manager := &countingCannonManager{
+		MockCannonManager: MockCannonManager{
+			bestCannon: &cannon.IonCannon{},
+			fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+		},
+		firing:  make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	server := newIdempotencyServer(manager)
+	defer server.Close()
+
+	bodies := make([]string, 3)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, bodies[0], _ = postAttack(t, server.URL, "a", idempotentAttackBody)
+	}()
+
+	// Duplicates arriving while the first attack is firing wait for it
+	<-manager.firing
+	for i := 1; i < len(bodies); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, bodies[i], _ = postAttack(t, server.URL, "a", idempotentAttackBody)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(manager.release)
+	wg.Wait()
+
+	if got := manager.fires.Load(); got != 1 {
+		t.Errorf("Cannon fired %d times, want 1", got)
+	}
+	for i, body := range bodies {
+		if body != bodies[0] {
+			t.Errorf("Response %d = %s, want %s", i, body, bodies[0])
+		}
+	}
+}
+
+func TestIdempotencyStore_Window(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := newIdempotencyStore(time.Minute, DefaultIdempotencyKeys)
+	store.now = func() time.Time { return now }
+
+	fingerprint := sha256.Sum256(nil)
+	resp, first, _ := store.begin("a", fingerprint)
+	if !first {
+		t.Fatal("begin() on a new key was not first")
+	}
+
+	// In-flight requests never expire
+	now = now.Add(time.Hour)
+	if _, first, _ := store.begin("a", fingerprint); first {
+		t.Error("begin() on an in-flight key was first")
+	}
+
+	store.finish(resp, &recordingWriter{ResponseWriter: httptest.NewRecorder(), status: http.StatusOK})
+	now = now.Add(30 * time.Second)
+	if _, first, _ := store.begin("a", fingerprint); first {
+		t.Error("begin() within the window was first")
+	}
+
+	now = now.Add(time.Minute)
+	if _, first, _ := store.begin("a", fingerprint); !first {
+		t.Error("begin() after the window was not first")
+	}
+}
+
+func TestIdempotencyStore_Capacity(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := newIdempotencyStore(time.Minute, 3)
+	store.now = func() time.Time { return now }
+
+	fingerprint := sha256.Sum256(nil)
+	begin := func(key string) (*idempotentResponse, bool, error) {
+		resp, first, err := store.begin(key, fingerprint)
+		if err == nil && first {
+			store.finish(resp, &recordingWriter{ResponseWriter: httptest.NewRecorder(), status: http.StatusOK})
+		}
+		now = now.Add(time.Second)
+		return resp, first, err
+	}
+
+	// "a" is still running, so "b" is the completed response closest to expiring
+	store.begin("a", fingerprint)
+	begin("b")
+	begin("c")
+
+	// A new key at capacity evicts the completed response closest to expiring
+	if _, first, err := begin("d"); err != nil || !first {
+		t.Fatalf("begin() at capacity = %v, %v, want a new key", first, err)
+	}
+	if len(store.responses) != 3 {
+		t.Errorf("Store holds %d keys, want 3", len(store.responses))
+	}
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if _, ok := store.responses[key]; ok != want {
+			t.Errorf("Key %q remembered = %v, want %v", key, ok, want)
+		}
+	}
+
+	// In-flight requests are never evicted
+	full := newIdempotencyStore(time.Minute, 1)
+	full.begin("a", fingerprint)
+	if _, _, err := full.begin("b", fingerprint); !errors.Is(err, errTooManyIdempotencyKeys) {
+		t.Errorf("begin() on a store full of in-flight keys error = %v, want errTooManyIdempotencyKeys", err)
+	}
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
+)
+
+// AttackJobs runs attacks in the background
+type AttackJobs interface {
+	Submit(req *attack.Request) (attack.Job, error)
+	Get(id string) (attack.Job, error)
+}
+
+// JobResponse is the response body of POST /attacks and GET /attacks/{id}.
+// Error describes why a failed job did not fire.
+type JobResponse struct {
+	attack.Job
+	Error *Problem `json:"error,omitempty"`
+}
+
+// handleSubmitJob queues an attack and answers straight away with its job
+func (h *Handler) handleSubmitJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	req, ok := h.decodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobs.Submit(req)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.logger.Info("Attack job queued",
+		slog.String("job_id", job.ID),
+		slog.Any("protocols", req.Protocols),
+		slog.Int("targets", len(req.Scan)),
+		slog.String("request_id", RequestIDFromContext(r.Context())),
+	)
+
+	w.Header().Set("Location", "/attacks/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	h.writeJob(w, r, job)
+}
+
+// handleGetJob reports the state of an attack job
+func (h *Handler) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job, err := h.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJob(w, r, job)
+}
+
+// writeJob writes the job body, describing the error of a failed job as a problem
+func (h *Handler) writeJob(w http.ResponseWriter, r *http.Request, job attack.Job) {
+	resp := JobResponse{Job: job}
+	if job.Err != nil {
+		problem := newProblem(r, job.Err)
+		resp.Error = &problem
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("Failed to write response",
+			slog.String("error", err.Error()),
+		)
+	}
+}
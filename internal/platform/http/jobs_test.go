@@ -0,0 +1,185 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+	"github.com/aitoroses/battlestation-codetest/internal/platform/metrics"
+)
+
+func TestHandler_AttackJobs(t *testing.T) {
+	manager := &MockCannonManager{
+		bestCannon: &cannon.IonCannon{},
+		fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+	}
+	coordinator := attack.NewCoordinator(manager)
+	jobs := attack.NewJobRunner(context.Background(), coordinator, attack.NewJobStore(10, time.Minute), 1)
+	handler := NewHandler(coordinator, nil, WithAttackJobs(jobs))
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	body := `{
+		"protocols": ["closest-enemies"],
+		"scan": [{"coordinates": {"x": 0, "y": 40}, "enemies": {"type": "soldier", "number": 10}}]
+	}`
+	resp, err := http.Post(server.URL+"/attacks", "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /attacks status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	var submitted JobResponse
+	if err := json.NewDecoder(resp.Body).Decode(&submitted); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if location := resp.Header.Get("Location"); location != "/attacks/"+submitted.ID {
+		t.Errorf("Location = %q, want /attacks/%s", location, submitted.ID)
+	}
+
+	jobs.Wait()
+
+	resp, err = http.Get(server.URL + "/attacks/" + submitted.ID)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var got JobResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || got.Status != attack.JobDone || got.Result == nil || got.Result.Casualties != 10 {
+		t.Errorf("GET /attacks/{id} = %d %+v, want a done job with 10 casualties", resp.StatusCode, got)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "unknown job", method: http.MethodGet, path: "/attacks/unknown", wantStatus: http.StatusNotFound, wantCode: "job_not_found"},
+		{name: "invalid request", method: http.MethodPost, path: "/attacks", body: `{"protocols": [], "scan": []}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, server.URL+tt.path, bytes.NewBufferString(tt.body))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var problem Problem
+			if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+				t.Fatalf("Failed to decode problem: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus || problem.Code != tt.wantCode {
+				t.Errorf("Got %d %s, want %d %s", resp.StatusCode, problem.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHandler_AttackJobs_Failed(t *testing.T) {
+	manager := &MockCannonManager{bestErr: cannon.ErrNoCannonAvailable}
+	coordinator := attack.NewCoordinator(manager)
+	jobs := attack.NewJobRunner(context.Background(), coordinator, attack.NewJobStore(10, time.Minute), 1)
+	handler := NewHandler(coordinator, nil, WithAttackJobs(jobs))
+
+	job, err := jobs.Submit(&attack.Request{
+		Protocols: []string{"closest-enemies"},
+		Scan: []attack.ScanPoint{{
+			Coordinates: target.Position{X: 0, Y: 40},
+			Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	jobs.Wait()
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/attacks/"+job.ID, nil))
+
+	var got JobResponse
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if rr.Code != http.StatusOK || got.Status != attack.JobFailed || got.Error == nil {
+		t.Fatalf("GET /attacks/{id} = %d %+v, want a failed job with an error", rr.Code, got)
+	}
+	if got.Error.Code != "no_cannon_available" || got.Error.Status != http.StatusServiceUnavailable {
+		t.Errorf("Job error = %+v, want no_cannon_available", got.Error)
+	}
+}
+
+func TestHandler_AttackJobs_RequestMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		manager    *MockCannonManager
+		wantLabels []string
+	}{
+		{
+			name: "done",
+			manager: &MockCannonManager{
+				bestCannon: &cannon.IonCannon{},
+				fireResp:   &cannon.FireResponse{Casualties: 10, Generation: 1},
+			},
+			wantLabels: []string{"most-enemies", "success", "200"},
+		},
+		{
+			name:       "failed",
+			manager:    &MockCannonManager{bestErr: cannon.ErrNoCannonAvailable},
+			wantLabels: []string{"most-enemies", "no_cannon_available", "503"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := attack.NewJobRunner(context.Background(), attack.NewCoordinator(tt.manager),
+				attack.NewJobStore(10, time.Minute), 1, attack.WithJobObserver(RequestMetrics{}))
+
+			counter := metrics.RequestTotal.WithLabelValues(tt.wantLabels...)
+			before := testutil.ToFloat64(counter)
+
+			req := &attack.Request{
+				Protocols: []string{"most-enemies"},
+				Scan: []attack.ScanPoint{{
+					Coordinates: target.Position{X: 0, Y: 40},
+					Enemies:     target.EnemyGroups{{Type: target.EnemyTypeSoldier, Number: 10}},
+				}},
+			}
+			if _, err := jobs.Submit(req); err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+			jobs.Wait()
+
+			// A background attack is counted like one answered synchronously
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("Expected the job to be counted once as %v, got %v", tt.wantLabels, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/protocol"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+	"github.com/aitoroses/battlestation-codetest/internal/platform/metrics"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// problemTypeBase prefixes the error code to build a stable problem type URI
+const problemTypeBase = "urn:battlestation:problem:"
+
+// errBadRequest marks requests that could not be read or parsed
+var errBadRequest = errors.New("bad request")
+
+// Problem is an RFC 7807 problem details response body
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          string         `json:"code"`
+	RequestID     string         `json:"request_id,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// InvalidParam describes a single request field that failed validation
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// problemType describes a class of errors
+type problemType struct {
+	status int
+	code   string
+	title  string
+}
+
+var (
+	problemTimeout               = problemType{http.StatusGatewayTimeout, "timeout", "Request deadline exceeded"}
+	problemCanceled              = problemType{http.StatusServiceUnavailable, "canceled", "Request canceled"}
+	problemBadRequest            = problemType{http.StatusBadRequest, "bad_request", "Malformed request"}
+	problemInvalidProtocol       = problemType{http.StatusBadRequest, "invalid_protocol", "Unknown protocol"}
+	problemIncompatibleProtocols = problemType{http.StatusBadRequest, "incompatible_protocols", "Incompatible protocols"}
+	problemNotScorable           = problemType{http.StatusBadRequest, "protocol_not_scorable", "Protocol cannot be scored"}
+	problemInvalidRequest        = problemType{http.StatusBadRequest, "invalid_request", "Invalid request"}
+	problemNoValidTargets        = problemType{http.StatusBadRequest, "no_valid_targets", "No valid targets"}
+	problemKeyReused             = problemType{http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency key reused"}
+	problemCannonNotFound        = problemType{http.StatusNotFound, "cannon_not_found", "Cannon not found"}
+	problemJobNotFound           = problemType{http.StatusNotFound, "job_not_found", "Attack job not found"}
+	problemTooManyJobs           = problemType{http.StatusServiceUnavailable, "too_many_jobs", "Too many attack jobs"}
+	problemShuttingDown          = problemType{http.StatusServiceUnavailable, "shutting_down", "Shutting down"}
+	problemIdempotencyFull       = problemType{http.StatusServiceUnavailable, "idempotency_store_full", "Idempotency store full"}
+	problemFireFailed            = problemType{http.StatusBadGateway, "fire_failed", "Cannon fire failed"}
+	problemNoCannonAvailable     = problemType{http.StatusServiceUnavailable, "no_cannon_available", "No cannon available"}
+	problemInternal              = problemType{http.StatusInternalServerError, "internal_error", "Internal error"}
+)
+
+// classifyError maps errors to the problem type reported to the client
+func classifyError(err error) problemType {
+	var validationErr *attack.ValidationError
+
+	switch {
+	case errors.Is(err, attack.ErrRequestTimeout), errors.Is(err, context.DeadlineExceeded):
+		return problemTimeout
+	case errors.Is(err, context.Canceled):
+		return problemCanceled
+	case errors.Is(err, errBadRequest):
+		return problemBadRequest
+	case errors.Is(err, errIdempotencyKeyReused):
+		return problemKeyReused
+	case errors.Is(err, errTooManyIdempotencyKeys):
+		return problemIdempotencyFull
+	case errors.Is(err, protocol.ErrInvalidProtocol):
+		return problemInvalidProtocol
+	case errors.Is(err, protocol.ErrIncompatibleProtocols):
+		return problemIncompatibleProtocols
+	case errors.Is(err, protocol.ErrNotScorable):
+		return problemNotScorable
+	case errors.As(err, &validationErr):
+		return problemInvalidRequest
+	case errors.Is(err, target.ErrNoValidTargets):
+		return problemNoValidTargets
+	case errors.Is(err, cannon.ErrCannonNotFound):
+		return problemCannonNotFound
+	case errors.Is(err, attack.ErrJobNotFound):
+		return problemJobNotFound
+	case errors.Is(err, attack.ErrTooManyJobs):
+		return problemTooManyJobs
+	case errors.Is(err, attack.ErrShuttingDown):
+		return problemShuttingDown
+	case errors.Is(err, attack.ErrFireFailed):
+		return problemFireFailed
+	case errors.Is(err, cannon.ErrNoCannonAvailable):
+		return problemNoCannonAvailable
+	default:
+		return problemInternal
+	}
+}
+
+// invalidParams lists every field validation error found in err
+func invalidParams(err error) []InvalidParam {
+	var errs attack.ValidationErrors
+	if !errors.As(err, &errs) {
+		var single *attack.ValidationError
+		if !errors.As(err, &single) {
+			return nil
+		}
+		errs = attack.ValidationErrors{single}
+	}
+
+	params := make([]InvalidParam, 0, len(errs))
+	for _, e := range errs {
+		params = append(params, InvalidParam{Name: e.Field, Reason: e.Err.Error()})
+	}
+	return params
+}
+
+// newProblem describes err as a problem details response body
+func newProblem(r *http.Request, err error) Problem {
+	pt := classifyError(err)
+	return Problem{
+		Type:          problemTypeBase + pt.code,
+		Title:         pt.title,
+		Status:        pt.status,
+		Detail:        err.Error(),
+		Instance:      r.URL.Path,
+		Code:          pt.code,
+		RequestID:     RequestIDFromContext(r.Context()),
+		InvalidParams: invalidParams(err),
+	}
+}
+
+// writeError writes err as a problem details response
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := newProblem(r, err)
+
+	h.logger.Error("Request error",
+		slog.String("error", err.Error()),
+		slog.String("code", problem.Code),
+		slog.Int("status_code", problem.Status),
+		slog.String("request_id", problem.RequestID),
+	)
+
+	metrics.RecordError("http", problem.Code)
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		h.logger.Error("Failed to write error response",
+			slog.String("error", err.Error()),
+		)
+	}
+}
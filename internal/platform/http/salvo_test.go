@@ -0,0 +1,184 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/attack"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// fleetCannonManager reserves every cannon of a fleet and fails fire with
+// the configured error for selected generations
+type fleetCannonManager struct {
+	cannons    []*cannon.IonCannon
+	reserveErr error
+	fireErr    map[cannon.Generation]error
+}
+
+func (m *fleetCannonManager) GetBestAvailable(ctx context.Context) (*cannon.IonCannon, error) {
+	if m.reserveErr != nil {
+		return nil, m.reserveErr
+	}
+	return m.cannons[0], nil
+}
+
+func (m *fleetCannonManager) Reserve(ctx context.Context, exclude ...*cannon.IonCannon) (*cannon.Reservation, error) {
+	c, err := m.GetBestAvailable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &cannon.Reservation{Cannon: c}, nil
+}
+
+func (m *fleetCannonManager) ReserveN(ctx context.Context, n int) ([]*cannon.Reservation, error) {
+	if m.reserveErr != nil {
+		return nil, m.reserveErr
+	}
+	var reservations []*cannon.Reservation
+	for _, c := range m.cannons[:min(n, len(m.cannons))] {
+		reservations = append(reservations, &cannon.Reservation{Cannon: c})
+	}
+	return reservations, nil
+}
+
+func (m *fleetCannonManager) Release(r *cannon.Reservation) {}
+
+func (m *fleetCannonManager) Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+	if err := m.fireErr[r.Cannon.Generation()]; err != nil {
+		return nil, err
+	}
+	return &cannon.FireResponse{Casualties: req.Enemies, Generation: int(r.Cannon.Generation())}, nil
+}
+
+func TestHandler_HandleSalvo(t *testing.T) {
+	validBody := `{
+		"protocols": ["closest-enemies"],
+		"scan": [
+			{"coordinates": {"x": 0, "y": 10}, "enemies": {"type": "soldier", "number": 5}},
+			{"coordinates": {"x": 0, "y": 20}, "enemies": {"type": "soldier", "number": 10}}
+		]
+	}`
+
+	tests := []struct {
+		name        string
+		requestBody string
+		reserveErr  error
+		fireErr     map[cannon.Generation]error
+		wantStatus  int
+		wantShots   []attack.Shot
+		wantCode    string
+	}{
+		{
+			name:        "every shot succeeds",
+			requestBody: validBody,
+			wantStatus:  http.StatusOK,
+			wantShots: []attack.Shot{
+				{Target: target.Position{X: 0, Y: 10}, Casualties: 5, Generation: 1},
+				{Target: target.Position{X: 0, Y: 20}, Casualties: 10, Generation: 2},
+			},
+		},
+		{
+			name:        "partial failure",
+			requestBody: validBody,
+			fireErr:     map[cannon.Generation]error{cannon.Generation2: errors.New("cannon busy")},
+			wantStatus:  http.StatusOK,
+			wantShots: []attack.Shot{
+				{Target: target.Position{X: 0, Y: 10}, Casualties: 5, Generation: 1},
+				{Target: target.Position{X: 0, Y: 20}, Generation: 2, Error: "cannon busy"},
+			},
+		},
+		{
+			name: "invalid scan point",
+			requestBody: `{
+				"protocols": ["closest-enemies"],
+				"scan": [{"coordinates": {"x": 0, "y": 10}, "enemies": {"type": "soldier", "number": 0}}]
+			}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_request",
+		},
+		{
+			name:        "no cannon available",
+			requestBody: validBody,
+			reserveErr:  cannon.ErrNoCannonAvailable,
+			wantStatus:  http.StatusServiceUnavailable,
+			wantCode:    "no_cannon_available",
+		},
+		{
+			name:        "every shot fails",
+			requestBody: validBody,
+			fireErr: map[cannon.Generation]error{
+				cannon.Generation1: errors.New("connection refused"),
+				cannon.Generation2: errors.New("cannon busy"),
+			},
+			wantStatus: http.StatusBadGateway,
+			wantCode:   "fire_failed",
+		},
+		{
+			name:        "every shot times out",
+			requestBody: validBody,
+			fireErr: map[cannon.Generation]error{
+				cannon.Generation1: fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+				cannon.Generation2: fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			},
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   "timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &fleetCannonManager{
+				cannons: []*cannon.IonCannon{
+					cannon.NewIonCannon(cannon.Generation1, "http://cannon1", nil),
+					cannon.NewIonCannon(cannon.Generation2, "http://cannon2", nil),
+				},
+				reserveErr: tt.reserveErr,
+				fireErr:    tt.fireErr,
+			}
+			handler := NewHandler(attack.NewCoordinator(manager), nil)
+
+			mux := http.NewServeMux()
+			handler.RegisterRoutes(mux)
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+"/attack/salvo", "application/json", bytes.NewBufferString(tt.requestBody))
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Handler returned wrong status code: got %v want %v", resp.StatusCode, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				var got attack.SalvoResponse
+				if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if !reflect.DeepEqual(got.Shots, tt.wantShots) {
+					t.Errorf("Unexpected shots:\ngot:  %+v\nwant: %+v", got.Shots, tt.wantShots)
+				}
+				return
+			}
+
+			var got Problem
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if got.Code != tt.wantCode || got.Status != tt.wantStatus {
+				t.Errorf("Handler returned wrong problem: got %q/%d want %q/%d (%s)", got.Code, got.Status, tt.wantCode, tt.wantStatus, got.Detail)
+			}
+		})
+	}
+}
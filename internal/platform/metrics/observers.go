@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aitoroses/battlestation-codetest/internal/domain/cannon"
+	"github.com/aitoroses/battlestation-codetest/internal/domain/target"
+)
+
+// CannonObserver records cannon events in Prometheus
+type CannonObserver struct{}
+
+// CannonFired records fire latency and outcome per generation
+func (CannonObserver) CannonFired(generation cannon.Generation, duration time.Duration, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	RecordCannonFire(strconv.Itoa(int(generation)), duration.Seconds(), status)
+}
+
+// CannonAvailability records whether a generation is ready to fire
+func (CannonObserver) CannonAvailability(generation cannon.Generation, available bool) {
+	value := 0.0
+	if available {
+		value = 1
+	}
+	UpdateCannonAvailability(strconv.Itoa(int(generation)), value)
+}
+
+// CannonTimedOut records a cannon call that ran out of its phase deadline
+func (CannonObserver) CannonTimedOut(generation cannon.Generation, phase cannon.Phase) {
+	RecordPhaseTimeout(string(phase))
+}
+
+// ProtocolObserver records protocol chain events in Prometheus
+type ProtocolObserver struct{}
+
+// TargetsSelected records the enemy types of the targets a protocol
+// combination chose from
+func (ProtocolObserver) TargetsSelected(protocols string, targets []*target.Target) {
+	for _, t := range targets {
+		RecordTargetProcessed(protocols, enemyTypeLabel(t))
+	}
+}
+
+// ProtocolApplied records selection time per protocol
+func (ProtocolObserver) ProtocolApplied(name string, duration time.Duration, input, output []*target.Target) {
+	RecordTargetSelection(name, duration.Seconds())
+}
+
+// enemyTypeLabel names the enemy type of a target, or "mixed" when it holds
+// several types
+func enemyTypeLabel(t *target.Target) string {
+	types := t.Enemies.Types()
+	if len(types) == 1 {
+		return string(types[0])
+	}
+	return "mixed"
+}
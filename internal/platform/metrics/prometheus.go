@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -9,14 +11,14 @@ var (
 	// Request metrics
 	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "battlestation_request_duration_seconds",
-		Help:    "Time taken to process attack requests",
+		Help:    "Time taken to process attack requests by protocol combination",
 		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1},
-	}, []string{"protocol"})
+	}, []string{"protocols"})
 
 	RequestTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "battlestation_requests_total",
-		Help: "Total number of attack requests",
-	}, []string{"protocol", "status"})
+		Help: "Total number of attack requests by protocol combination, outcome and HTTP status code",
+	}, []string{"protocols", "outcome", "code"})
 
 	// Target selection metrics
 	TargetSelectionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
@@ -27,7 +29,7 @@ var (
 
 	TargetsProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "battlestation_targets_processed_total",
-		Help: "Total number of targets processed",
+		Help: "Total number of targets processed, counted once per selection by protocol combination and enemy type",
 	}, []string{"protocol", "type"})
 
 	// Ion cannon metrics
@@ -47,6 +49,16 @@ var (
 		Help: "Total number of ion cannon fires",
 	}, []string{"generation", "status"})
 
+	CannonCircuitState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "battlestation_ion_cannon_circuit_state",
+		Help: "Circuit breaker state of ion cannons (0 closed, 1 half-open, 2 open)",
+	}, []string{"generation"})
+
+	PhaseTimeouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "battlestation_phase_timeouts_total",
+		Help: "Total number of calls that ran out of their phase deadline (status, fire, request)",
+	}, []string{"phase"})
+
 	// Error metrics
 	ErrorTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "battlestation_errors_total",
@@ -54,14 +66,11 @@ var (
 	}, []string{"type", "operation"})
 )
 
-// RecordRequestDuration records the duration of an attack request
-func RecordRequestDuration(protocol string, duration float64) {
-	RequestDuration.WithLabelValues(protocol).Observe(duration)
-}
-
-// RecordRequestComplete records a completed request
-func RecordRequestComplete(protocol string, status string) {
-	RequestTotal.WithLabelValues(protocol, status).Inc()
+// RecordRequest records the duration and outcome of an attack request once,
+// labelled by its normalized protocol combination
+func RecordRequest(protocols, outcome string, statusCode int, duration float64) {
+	RequestDuration.WithLabelValues(protocols).Observe(duration)
+	RequestTotal.WithLabelValues(protocols, outcome, strconv.Itoa(statusCode)).Inc()
 }
 
 // RecordTargetSelection records target selection metrics
@@ -85,6 +94,16 @@ func RecordCannonFire(generation string, duration float64, status string) {
 	CannonFireTotal.WithLabelValues(generation, status).Inc()
 }
 
+// UpdateCannonCircuitState updates the circuit breaker state of an ion cannon
+func UpdateCannonCircuitState(generation string, state float64) {
+	CannonCircuitState.WithLabelValues(generation).Set(state)
+}
+
+// RecordPhaseTimeout records a call that ran out of its phase deadline
+func RecordPhaseTimeout(phase string) {
+	PhaseTimeouts.WithLabelValues(phase).Inc()
+}
+
 // RecordError records an error
 func RecordError(errorType, operation string) {
 	ErrorTotal.WithLabelValues(errorType, operation).Inc()
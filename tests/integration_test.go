@@ -67,7 +67,18 @@ func (m *MockCannonManager) GetBestAvailable(ctx context.Context) (*cannon.IonCa
 	return &cannon.IonCannon{}, nil
 }
 
-func (m *MockCannonManager) Fire(ctx context.Context, c *cannon.IonCannon, req *cannon.FireRequest) (*cannon.FireResponse, error) {
+func (m *MockCannonManager) Reserve(ctx context.Context, exclude ...*cannon.IonCannon) (*cannon.Reservation, error) {
+	return &cannon.Reservation{Cannon: &cannon.IonCannon{}}, nil
+}
+
+func (m *MockCannonManager) ReserveN(ctx context.Context, n int) ([]*cannon.Reservation, error) {
+	r, _ := m.Reserve(ctx)
+	return []*cannon.Reservation{r}, nil
+}
+
+func (m *MockCannonManager) Release(r *cannon.Reservation) {}
+
+func (m *MockCannonManager) Commit(ctx context.Context, r *cannon.Reservation, req *cannon.FireRequest) (*cannon.FireResponse, error) {
 	// Parse expected output to determine casualties and generation
 	var expected attack.Response
 	if err := json.Unmarshal([]byte(m.expectedOutput), &expected); err != nil {